@@ -19,6 +19,10 @@ type AdapterFactory = () => IDebugAdapter;
 export class AdapterRegistry {
   private adapters: Map<DebugLanguage, AdapterFactory> = new Map();
   private extensionMap: Map<string, DebugLanguage> = new Map();
+  // Alternate names accepted for a language (e.g. 'node' for JavaScript)
+  private aliases: Map<string, DebugLanguage> = new Map([['node', DebugLanguage.JAVASCRIPT]]);
+  // Project manifests that identify a language by file name (e.g. Cargo.toml)
+  private manifestMap: Map<string, DebugLanguage> = new Map([['cargo.toml', DebugLanguage.RUST]]);
 
   constructor() {
     // Initialize file extension mappings
@@ -83,9 +87,23 @@ export class AdapterRegistry {
   }
 
   /**
-   * Detect language from file extension
+   * Resolve a language name or alias to a language
+   */
+  resolveLanguage(name: string): DebugLanguage | null {
+    const normalized = name.toLowerCase();
+    const language = Object.values(DebugLanguage).find((l) => l === normalized);
+    return language ?? this.aliases.get(normalized) ?? null;
+  }
+
+  /**
+   * Detect language from file extension or project manifest name
    */
   detectLanguage(filePath: string): DebugLanguage | null {
+    const fileName = filePath.split(/[\\/]/).pop()?.toLowerCase() ?? '';
+    const manifestLanguage = this.manifestMap.get(fileName);
+    if (manifestLanguage) {
+      return manifestLanguage;
+    }
     const ext = this.getExtension(filePath).toLowerCase();
     return this.extensionMap.get(ext) ?? null;
   }
@@ -6,13 +6,19 @@
  */
 
 import * as path from 'path';
+import * as os from 'os';
+import * as fs from 'fs/promises';
+import { execFile } from 'child_process';
+import { promisify } from 'util';
 import { DebugProtocol } from '@vscode/debugprotocol';
-import { DebugLanguage, LaunchParams } from '../../session/types.js';
+import { DebugLanguage, LaunchParams, AttachParams } from '../../session/types.js';
 import {
   IDebugAdapter,
   AdapterCommand,
   ValidationResult,
-  InstallationStatus
+  InstallationStatus,
+  BuildDiagnostic,
+  BuildValidationResult
 } from '../types.js';
 import {
   executeCommand,
@@ -22,10 +28,46 @@ import {
   pathExists
 } from '../adapter-installer.js';
 
+const execFileAsync = promisify(execFile);
+
+/**
+ * Whether a launch debugs a package's tests (dlv test) rather than its main:
+ * asked for explicitly, implied by a test filter or a _test.go entry point
+ */
+export function isGoTestLaunch(params: LaunchParams): boolean {
+  return params.test ?? (params.testFilter !== undefined || params.scriptPath.endsWith('_test.go'));
+}
+
+/**
+ * Parse `go build` output into diagnostics. Errors look like
+ * "./main.go:12:5: undefined: foo"; tab-indented lines continue the previous one.
+ */
+export function parseGoBuildOutput(output: string, cwd: string): BuildDiagnostic[] {
+  const diagnostics: BuildDiagnostic[] = [];
+
+  for (const line of output.split('\n')) {
+    const match = line.match(/^(.+?\.go):(\d+)(?::(\d+))?: (.*)$/);
+    if (match) {
+      diagnostics.push({
+        file: path.resolve(cwd, match[1]),
+        line: parseInt(match[2], 10),
+        column: match[3] ? parseInt(match[3], 10) : undefined,
+        message: match[4]
+      });
+    } else if (line.startsWith('\t') && diagnostics.length > 0) {
+      diagnostics[diagnostics.length - 1].message += `\n${line.trim()}`;
+    }
+  }
+
+  return diagnostics;
+}
+
 export class GoAdapter implements IDebugAdapter {
   readonly language = DebugLanguage.GO;
   readonly name = 'Go Debug Adapter (Delve)';
   readonly runtime = 'go';
+  readonly attachesToDapServer = true; // dlv --headless / dlv dap --listen speak DAP
+  readonly supportsTests = true; // mode: 'test', like dlv test
 
   private dlvPath: string = 'dlv';
   private cachedInstallStatus: InstallationStatus | null = null;
@@ -178,6 +220,61 @@ export class GoAdapter implements IDebugAdapter {
     return 'go';
   }
 
+  async validateBuild(params: LaunchParams): Promise<BuildValidationResult> {
+    const target = path.resolve(params.cwd ?? process.cwd(), params.scriptPath);
+    const stat = await fs.stat(target).catch(() => undefined);
+
+    // Delve builds a .go file or a package directory; anything else is a prebuilt binary
+    const isGoFile = target.endsWith('.go');
+    if (!stat || (!isGoFile && !stat.isDirectory())) {
+      return {
+        success: true,
+        skipped: true,
+        diagnostics: [],
+        message: `${params.scriptPath} is not Go source or a package directory; nothing to build`
+      };
+    }
+
+    // Tests build per package: compile the test binary of the file's directory
+    const test = isGoTestLaunch(params);
+    const buildDir = isGoFile
+      ? (params.cwd && !test ? path.resolve(params.cwd) : path.dirname(target))
+      : target;
+    const args = test
+      ? ['test', '-c', '-o', os.devNull, '.']
+      : ['build', '-o', os.devNull, isGoFile ? target : '.'];
+    const command = `go ${args.join(' ')}`;
+
+    try {
+      await execFileAsync('go', args, {
+        cwd: buildDir,
+        env: { ...process.env, ...params.env },
+        timeout: 120000,
+        maxBuffer: 10 * 1024 * 1024
+      });
+      return { success: true, command, diagnostics: [], message: 'Build succeeded' };
+    } catch (error) {
+      const { stderr, stdout, message, code } = error as NodeJS.ErrnoException & {
+        stderr?: string;
+        stdout?: string;
+      };
+      if (code === 'ENOENT') {
+        return { success: false, command, diagnostics: [], message: 'Go is not installed or not in PATH' };
+      }
+      const output = `${stderr ?? ''}${stdout ?? ''}`.trim() || message;
+      const diagnostics = parseGoBuildOutput(output, buildDir);
+      return {
+        success: false,
+        command,
+        diagnostics,
+        output: diagnostics.length === 0 ? output : undefined,
+        message: diagnostics.length > 0
+          ? `Build failed with ${diagnostics.length} error(s); fix them before debugging`
+          : 'Build failed'
+      };
+    }
+  }
+
   buildLaunchConfig(
     params: LaunchParams,
     executablePath: string
@@ -185,6 +282,10 @@ export class GoAdapter implements IDebugAdapter {
     // Determine if this is a file or a package
     const isGoFile = params.scriptPath.endsWith('.go');
 
+    if (isGoTestLaunch(params)) {
+      return this.buildTestLaunchConfig(params, isGoFile);
+    }
+
     const config: Record<string, unknown> = {
       type: 'go',
       request: 'launch',
@@ -192,7 +293,8 @@ export class GoAdapter implements IDebugAdapter {
       mode: isGoFile ? 'debug' : 'auto',
       program: params.scriptPath,
       args: params.args ?? [],
-      cwd: params.cwd ?? process.cwd(),
+      // A remote server resolves paths on its own filesystem
+      cwd: params.cwd ?? (params.port === undefined ? process.cwd() : undefined),
       env: params.env ?? {},
       stopOnEntry: params.stopOnEntry ?? false
     };
@@ -200,6 +302,54 @@ export class GoAdapter implements IDebugAdapter {
     return config as DebugProtocol.LaunchRequestArguments;
   }
 
+  /**
+   * Launch the package's test binary, verbose so each test's PASS/FAIL and
+   * assertion messages reach the output buffer
+   */
+  private buildTestLaunchConfig(
+    params: LaunchParams,
+    isGoFile: boolean
+  ): DebugProtocol.LaunchRequestArguments {
+    const remote = params.port !== undefined;
+    const pkg = isGoFile ? path.dirname(params.scriptPath) : params.scriptPath;
+    // A remote server resolves paths on its own filesystem
+    const program = remote ? pkg : path.resolve(params.cwd ?? process.cwd(), pkg);
+
+    const testArgs = ['-test.v'];
+    if (params.testFilter) {
+      testArgs.push('-test.run', params.testFilter);
+    }
+
+    const config: Record<string, unknown> = {
+      type: 'go',
+      request: 'launch',
+      name: 'MCP Debug Go Test',
+      mode: 'test',
+      program,
+      args: [...testArgs, ...(params.args ?? [])],
+      // go test runs tests from the package directory
+      cwd: params.cwd ?? (remote ? undefined : program),
+      env: params.env ?? {},
+      stopOnEntry: params.stopOnEntry ?? false
+    };
+
+    return config as DebugProtocol.LaunchRequestArguments;
+  }
+
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments {
+    // 'local' attaches dlv to a PID; 'remote' talks to an already-running headless server
+    const config: Record<string, unknown> = {
+      type: 'go',
+      request: 'attach',
+      name: 'MCP Debug Go',
+      mode: params.processId !== undefined ? 'local' : 'remote',
+      processId: params.processId,
+      cwd: params.cwd ?? process.cwd()
+    };
+
+    return config as DebugProtocol.AttachRequestArguments;
+  }
+
   getFileExtensions(): string[] {
     return ['.go'];
   }
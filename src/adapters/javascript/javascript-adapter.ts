@@ -17,7 +17,7 @@
 import * as path from 'path';
 import * as fs from 'fs/promises';
 import { DebugProtocol } from '@vscode/debugprotocol';
-import { DebugLanguage, LaunchParams } from '../../session/types.js';
+import { DebugLanguage, LaunchParams, AttachParams } from '../../session/types.js';
 import {
   IDebugAdapter,
   AdapterCommand,
@@ -39,6 +39,8 @@ export class JavaScriptAdapter implements IDebugAdapter {
   readonly language = DebugLanguage.JAVASCRIPT;
   readonly name = 'JavaScript Debug Adapter (vscode-js-debug)';
   readonly runtime = 'node';
+  readonly attachesToDapServer = false; // host/port is a Node inspector, not DAP
+  readonly supportsTests = false;
 
   private nodePath: string = 'node';
   private cachedInstallStatus: InstallationStatus | null = null;
@@ -224,7 +226,9 @@ export class JavaScriptAdapter implements IDebugAdapter {
     // Use default 'node'
     const version = await getNodeVersion();
     if (!version) {
-      throw new Error('Node.js not found in PATH');
+      throw new Error(
+        'Node.js (`node`) was not found on PATH. Install Node.js 18+ or pass executablePath to create_debug_session.'
+      );
     }
 
     return this.nodePath;
@@ -240,6 +244,7 @@ export class JavaScriptAdapter implements IDebugAdapter {
                          params.scriptPath.endsWith('.mts') ||
                          params.scriptPath.endsWith('.cts');
 
+    const cwd = params.cwd ?? process.cwd();
     const config: Record<string, unknown> = {
       type: 'pwa-node',
       request: 'launch',
@@ -247,10 +252,17 @@ export class JavaScriptAdapter implements IDebugAdapter {
       program: params.scriptPath,
       runtimeExecutable: executablePath,
       args: params.args ?? [],
-      cwd: params.cwd ?? process.cwd(),
+      cwd,
       env: params.env ?? {},
       stopOnEntry: params.stopOnEntry ?? false,
       console: 'internalConsole',
+      // Bind breakpoints set in .ts sources to the transpiled .js and map stacks back
+      sourceMaps: true,
+      outFiles: [
+        path.join(cwd, '**', '*.js'),
+        path.join(path.dirname(params.scriptPath), '**', '*.js'),
+        '!**/node_modules/**'
+      ],
       skipFiles: ['<node_internals>/**'],
       resolveSourceMapLocations: ['**', '!**/node_modules/**']
     };
@@ -264,6 +276,28 @@ export class JavaScriptAdapter implements IDebugAdapter {
     return config as DebugProtocol.LaunchRequestArguments;
   }
 
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments {
+    const config: Record<string, unknown> = {
+      type: 'pwa-node',
+      request: 'attach',
+      name: 'MCP Debug Node.js',
+      cwd: params.cwd ?? process.cwd(),
+      sourceMaps: true,
+      skipFiles: ['<node_internals>/**'],
+      resolveSourceMapLocations: ['**', '!**/node_modules/**']
+    };
+
+    if (params.processId !== undefined) {
+      // js-debug enables the inspector on the target via SIGUSR1
+      config.processId = String(params.processId);
+    } else {
+      config.address = params.host ?? '127.0.0.1';
+      config.port = params.port ?? 9229;
+    }
+
+    return config as DebugProtocol.AttachRequestArguments;
+  }
+
   getFileExtensions(): string[] {
     return ['.js', '.mjs', '.cjs', '.jsx', '.ts', '.mts', '.cts', '.tsx'];
   }
@@ -276,6 +310,8 @@ export class TypeScriptAdapter implements IDebugAdapter {
   readonly language = DebugLanguage.TYPESCRIPT;
   readonly name = 'TypeScript Debug Adapter (vscode-js-debug)';
   readonly runtime = 'node';
+  readonly attachesToDapServer = false;
+  readonly supportsTests = false;
 
   private jsAdapter = new JavaScriptAdapter();
 
@@ -306,6 +342,10 @@ export class TypeScriptAdapter implements IDebugAdapter {
     return this.jsAdapter.buildLaunchConfig(params, executablePath);
   }
 
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments {
+    return this.jsAdapter.buildAttachConfig(params);
+  }
+
   getFileExtensions(): string[] {
     return ['.ts', '.mts', '.cts', '.tsx'];
   }
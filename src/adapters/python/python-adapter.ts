@@ -6,7 +6,7 @@
  */
 
 import { DebugProtocol } from '@vscode/debugprotocol';
-import { DebugLanguage, LaunchParams } from '../../session/types.js';
+import { DebugLanguage, LaunchParams, AttachParams } from '../../session/types.js';
 import {
   IDebugAdapter,
   AdapterCommand,
@@ -25,6 +25,8 @@ export class PythonAdapter implements IDebugAdapter {
   readonly language = DebugLanguage.PYTHON;
   readonly name = 'Python Debug Adapter (debugpy)';
   readonly runtime = 'python';
+  readonly attachesToDapServer = true; // python -m debugpy --listen serves DAP
+  readonly supportsTests = false;
 
   private pythonPath: string = 'python3';
   private cachedInstallStatus: InstallationStatus | null = null;
@@ -95,7 +97,15 @@ export class PythonAdapter implements IDebugAdapter {
     }
 
     // Install debugpy via pip
-    await installPipPackage('debugpy', this.pythonPath);
+    try {
+      await installPipPackage('debugpy', this.pythonPath);
+    } catch (error) {
+      const reason = error instanceof Error ? error.message.split('\n')[0] : String(error);
+      throw new Error(
+        `debugpy is not installed and automatic installation failed (${reason}). ` +
+        `Run: ${this.pythonPath} -m pip install debugpy`
+      );
+    }
 
     // Clear cached status
     this.cachedInstallStatus = null;
@@ -219,6 +229,22 @@ export class PythonAdapter implements IDebugAdapter {
     return config as DebugProtocol.LaunchRequestArguments;
   }
 
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments {
+    // With a PID, debugpy injects itself into the process;
+    // with host/port we are already connected to the debugpy listener
+    const config: Record<string, unknown> = {
+      type: 'python',
+      request: 'attach',
+      name: 'MCP Debug Python',
+      processId: params.processId,
+      cwd: params.cwd ?? process.cwd(),
+      justMyCode: false,
+      redirectOutput: true
+    };
+
+    return config as DebugProtocol.AttachRequestArguments;
+  }
+
   getFileExtensions(): string[] {
     return ['.py', '.pyw'];
   }
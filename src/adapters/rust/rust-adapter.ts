@@ -8,13 +8,17 @@
 import * as path from 'path';
 import * as fs from 'fs/promises';
 import * as os from 'os';
+import { execFile } from 'child_process';
+import { promisify } from 'util';
 import { DebugProtocol } from '@vscode/debugprotocol';
-import { DebugLanguage, LaunchParams } from '../../session/types.js';
+import { DebugLanguage, LaunchParams, AttachParams } from '../../session/types.js';
 import {
   IDebugAdapter,
   AdapterCommand,
   ValidationResult,
-  InstallationStatus
+  InstallationStatus,
+  BuildDiagnostic,
+  BuildValidationResult
 } from '../types.js';
 import {
   executeCommand,
@@ -28,12 +32,123 @@ import {
   getPlatformInfo
 } from '../adapter-installer.js';
 
+const execFileAsync = promisify(execFile);
+
+/**
+ * A binary built by cargo
+ */
+export interface CargoExecutable {
+  name: string;
+  /** Crate root the binary was built from, e.g. src/main.rs */
+  srcPath: string;
+  path: string;
+}
+
+/**
+ * The fields we use from a line of `cargo build --message-format=json`
+ */
+interface CargoMessage {
+  reason: string;
+  message?: {
+    level: string;
+    message: string;
+    spans?: {
+      file_name: string;
+      line_start: number;
+      column_start: number;
+      is_primary: boolean;
+    }[];
+  };
+  target?: { name: string; src_path: string };
+  executable?: string | null;
+}
+
+/**
+ * Parse `cargo build --message-format=json` output into compile errors and
+ * the executables it produced. Diagnostic paths are relative to manifestDir.
+ */
+export function parseCargoBuildOutput(
+  output: string,
+  manifestDir: string
+): { diagnostics: BuildDiagnostic[]; executables: CargoExecutable[] } {
+  const diagnostics: BuildDiagnostic[] = [];
+  const executables: CargoExecutable[] = [];
+
+  for (const line of output.split('\n')) {
+    if (!line.startsWith('{')) {
+      continue;
+    }
+    let entry: CargoMessage;
+    try {
+      entry = JSON.parse(line) as CargoMessage;
+    } catch {
+      continue;
+    }
+
+    if (entry.reason === 'compiler-message' && entry.message?.level === 'error') {
+      const span = entry.message.spans?.find((s) => s.is_primary);
+      if (span) {
+        diagnostics.push({
+          file: path.resolve(manifestDir, span.file_name),
+          line: span.line_start,
+          column: span.column_start,
+          message: entry.message.message
+        });
+      }
+    } else if (entry.reason === 'compiler-artifact' && entry.executable && entry.target) {
+      executables.push({
+        name: entry.target.name,
+        srcPath: entry.target.src_path,
+        path: entry.executable
+      });
+    }
+  }
+
+  return { diagnostics, executables };
+}
+
+/**
+ * Pick the binary built from sourceFile (an absolute .rs path), or the only
+ * binary when there is just one. Undefined when nothing matches.
+ */
+export function selectCargoExecutable(
+  executables: CargoExecutable[],
+  sourceFile?: string
+): CargoExecutable | undefined {
+  return (
+    executables.find((e) => sourceFile !== undefined && e.srcPath === sourceFile) ??
+    (executables.length === 1 ? executables[0] : undefined)
+  );
+}
+
+/**
+ * Find the Cargo.toml of the package containing a path
+ */
+async function findCargoManifest(start: string): Promise<string | null> {
+  let dir = start;
+  for (;;) {
+    const manifest = path.join(dir, 'Cargo.toml');
+    if (await pathExists(manifest)) {
+      return manifest;
+    }
+    const parent = path.dirname(dir);
+    if (parent === dir) {
+      return null;
+    }
+    dir = parent;
+  }
+}
+
 export class RustAdapter implements IDebugAdapter {
   readonly language = DebugLanguage.RUST;
   readonly name = 'Rust Debug Adapter (CodeLLDB)';
   readonly runtime = 'rust';
+  readonly attachesToDapServer = false;
+  readonly supportsTests = false;
 
   private cachedInstallStatus: InstallationStatus | null = null;
+  // LLDB commands that load the toolchain's Rust formatters
+  private formatterCommands: string[] = [];
 
   async checkInstallation(): Promise<InstallationStatus> {
     if (this.cachedInstallStatus) {
@@ -165,9 +280,19 @@ export class RustAdapter implements IDebugAdapter {
     // Ensure installed
     const status = await this.checkInstallation();
     if (!status.installed) {
-      await this.install();
+      try {
+        await this.install();
+      } catch (error) {
+        const reason = error instanceof Error ? error.message : String(error);
+        throw new Error(
+          `CodeLLDB is not installed and could not be installed automatically (${reason}). ` +
+          'Download the codelldb .vsix for your platform from https://github.com/vadimcn/codelldb/releases ' +
+          `and unzip it into ${getAdapterPath('codelldb')}`
+        );
+      }
     }
 
+    this.formatterCommands = await this.getFormatterCommands();
     const adapterPath = this.getCodeLLDBPath();
 
     return {
@@ -177,6 +302,106 @@ export class RustAdapter implements IDebugAdapter {
     };
   }
 
+  /**
+   * Commands that load the formatters shipped with the Rust toolchain (what
+   * rust-lldb does), so enums, Option and Result display as Rust values
+   */
+  private async getFormatterCommands(): Promise<string[]> {
+    try {
+      const { stdout } = await executeCommand('rustc --print sysroot');
+      const etc = path.join(stdout.trim(), 'lib', 'rustlib', 'etc');
+      const lookup = path.join(etc, 'lldb_lookup.py');
+      const commands = path.join(etc, 'lldb_commands');
+      if (!(await pathExists(lookup)) || !(await pathExists(commands))) {
+        return [];
+      }
+      return [`command script import "${lookup}"`, `command source -s 0 "${commands}"`];
+    } catch {
+      return [];
+    }
+  }
+
+  async validateBuild(params: LaunchParams): Promise<BuildValidationResult> {
+    const target = path.resolve(params.cwd ?? process.cwd(), params.scriptPath);
+    const stat = await fs.stat(target).catch(() => undefined);
+    const isManifest = path.basename(target) === 'Cargo.toml';
+    const isSource = target.endsWith('.rs');
+
+    if (!stat || (stat.isFile() && !isManifest && !isSource)) {
+      return {
+        success: true,
+        skipped: true,
+        diagnostics: [],
+        message: `${params.scriptPath} is not Rust source or a Cargo project; nothing to build`
+      };
+    }
+
+    const manifest = isManifest
+      ? target
+      : await findCargoManifest(stat.isDirectory() ? target : path.dirname(target));
+    if (!manifest) {
+      return {
+        success: false,
+        diagnostics: [],
+        message: `No Cargo.toml found for ${params.scriptPath}; pass a compiled binary or a path inside a Cargo project`
+      };
+    }
+
+    const manifestDir = path.dirname(manifest);
+    const args = ['build', '--message-format=json', '--manifest-path', manifest];
+    const command = `cargo ${args.join(' ')}`;
+
+    let stdout: string;
+    let stderr: string;
+    try {
+      ({ stdout, stderr } = await execFileAsync('cargo', args, {
+        cwd: manifestDir,
+        env: { ...process.env, ...params.env },
+        timeout: 600000,
+        maxBuffer: 50 * 1024 * 1024
+      }));
+    } catch (error) {
+      const failure = error as NodeJS.ErrnoException & { stdout?: string; stderr?: string };
+      if (failure.code === 'ENOENT') {
+        return { success: false, command, diagnostics: [], message: 'Cargo is not installed or not in PATH' };
+      }
+      const { diagnostics } = parseCargoBuildOutput(failure.stdout ?? '', manifestDir);
+      const output = (failure.stderr ?? '').trim() || failure.message;
+      return {
+        success: false,
+        command,
+        diagnostics,
+        output: diagnostics.length === 0 ? output : undefined,
+        message: diagnostics.length > 0
+          ? `Build failed with ${diagnostics.length} error(s); fix them before debugging`
+          : 'Build failed'
+      };
+    }
+
+    const { executables } = parseCargoBuildOutput(stdout, manifestDir);
+    const executable = selectCargoExecutable(executables, isSource ? target : undefined);
+    if (!executable) {
+      const names = executables.map((e) => e.name).join(', ');
+      return {
+        success: false,
+        command,
+        diagnostics: [],
+        output: stderr.trim() || undefined,
+        message: executables.length === 0
+          ? 'Build succeeded but produced no executable (is this a library crate?)'
+          : `Build produced several binaries (${names}); pass the .rs file of the one to debug, e.g. src/bin/<name>.rs`
+      };
+    }
+
+    return {
+      success: true,
+      command,
+      diagnostics: [],
+      artifact: executable.path,
+      message: `Built ${executable.name}`
+    };
+  }
+
   async validateEnvironment(): Promise<ValidationResult> {
     const errors: string[] = [];
     const warnings: string[] = [];
@@ -238,8 +463,15 @@ export class RustAdapter implements IDebugAdapter {
     params: LaunchParams,
     _executablePath: string
   ): DebugProtocol.LaunchRequestArguments {
-    // Always use the program directly - scriptPath should be a compiled binary
-    // If it's a source file (.rs), we'd need to compile it first
+    // scriptPath must be a compiled binary; validateBuild swaps sources for
+    // the binary cargo built
+    if (params.scriptPath.endsWith('.rs') || path.basename(params.scriptPath) === 'Cargo.toml') {
+      throw new Error(
+        `${params.scriptPath} must be built before it can be debugged. ` +
+        'Pass the compiled binary, or leave validateBuild on to build it with cargo.'
+      );
+    }
+
     const config: Record<string, unknown> = {
       type: 'lldb',
       request: 'launch',
@@ -249,12 +481,30 @@ export class RustAdapter implements IDebugAdapter {
       cwd: params.cwd ?? path.dirname(params.scriptPath),
       env: params.env ?? {},
       stopOnEntry: params.stopOnEntry ?? false,
-      sourceLanguages: ['rust']
+      sourceLanguages: ['rust'],
+      initCommands: this.formatterCommands
     };
 
     return config as DebugProtocol.LaunchRequestArguments;
   }
 
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments {
+    if (params.processId === undefined) {
+      throw new Error('CodeLLDB attach requires a processId');
+    }
+
+    const config: Record<string, unknown> = {
+      type: 'lldb',
+      request: 'attach',
+      name: 'MCP Debug Rust',
+      pid: params.processId,
+      sourceLanguages: ['rust'],
+      initCommands: this.formatterCommands
+    };
+
+    return config as DebugProtocol.AttachRequestArguments;
+  }
+
   getFileExtensions(): string[] {
     return ['.rs'];
   }
@@ -5,7 +5,7 @@
  */
 
 import { DebugProtocol } from '@vscode/debugprotocol';
-import { DebugLanguage, LaunchParams } from '../session/types.js';
+import { DebugLanguage, LaunchParams, AttachParams } from '../session/types.js';
 
 /**
  * Command to launch an adapter process
@@ -35,6 +35,37 @@ export interface ValidationResult {
   warnings: string[];
 }
 
+/**
+ * A compiler error or warning from a pre-launch build check
+ */
+export interface BuildDiagnostic {
+  /** Absolute path of the source file */
+  file: string;
+  /** Line number (1-based) */
+  line: number;
+  /** Column number (1-based), if the compiler reports it */
+  column?: number;
+  message: string;
+}
+
+/**
+ * Result of compiling the target before launching it
+ */
+export interface BuildValidationResult {
+  /** Whether the target built (or there was nothing to build) */
+  success: boolean;
+  /** Set when no build was run, e.g. for a prebuilt binary */
+  skipped?: boolean;
+  /** The build command that was run */
+  command?: string;
+  diagnostics: BuildDiagnostic[];
+  /** Raw compiler output, for failures that produced no parseable diagnostics */
+  output?: string;
+  /** Executable produced by the build, launched in place of the source path */
+  artifact?: string;
+  message: string;
+}
+
 /**
  * Status of adapter installation
  */
@@ -62,6 +93,15 @@ export interface IDebugAdapter {
   /** Required runtime (e.g., 'node', 'python') */
   readonly runtime: string;
 
+  /**
+   * Whether a host/port attach target is itself a DAP server that we dial
+   * directly, rather than an endpoint the spawned adapter connects to
+   */
+  readonly attachesToDapServer: boolean;
+
+  /** Whether a launch can run the target's tests instead of its main program */
+  readonly supportsTests: boolean;
+
   /**
    * Check if the adapter is installed
    */
@@ -88,6 +128,12 @@ export interface IDebugAdapter {
    */
   resolveExecutablePath(preferredPath?: string): Promise<string>;
 
+  /**
+   * Compile the launch target without running it, for languages with a
+   * build step. Adapters without one leave this undefined.
+   */
+  validateBuild?(params: LaunchParams): Promise<BuildValidationResult>;
+
   /**
    * Build the launch configuration for this adapter
    */
@@ -96,6 +142,11 @@ export interface IDebugAdapter {
     executablePath: string
   ): DebugProtocol.LaunchRequestArguments;
 
+  /**
+   * Build the attach configuration for this adapter
+   */
+  buildAttachConfig(params: AttachParams): DebugProtocol.AttachRequestArguments;
+
   /**
    * Get file extensions handled by this adapter
    */
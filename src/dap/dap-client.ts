@@ -10,9 +10,11 @@ import { ChildProcess, spawn, SpawnOptions } from 'child_process';
 import { Socket, createConnection } from 'net';
 import { DebugProtocol } from '@vscode/debugprotocol';
 import { DapMessageParser, encodeMessage } from './message-parser.js';
+import { DapTracer, DapTraceOptions } from './dap-trace.js';
 import {
   BreakpointInfo,
   StackFrame,
+  StackTracePage,
   Variable,
   Scope,
   ThreadInfo,
@@ -39,12 +41,17 @@ export interface DapClientConfig {
   cwd?: string;
   /** Request timeout in milliseconds */
   timeout?: number;
-  /** Connection mode: 'stdio' (default) or 'tcp' */
-  mode?: 'stdio' | 'tcp';
-  /** For TCP mode: port to connect to (optional, will parse from stderr if not provided) */
+  /**
+   * Connection mode: 'stdio' (default), 'tcp' (spawn adapter and connect to its port),
+   * or 'connect' (dial an already-running adapter at host:port without spawning)
+   */
+  mode?: 'stdio' | 'tcp' | 'connect';
+  /** For TCP/connect mode: port to connect to (optional for TCP, will parse from stderr if not provided) */
   port?: number;
-  /** For TCP mode: host to connect to (default: 127.0.0.1) */
+  /** For TCP/connect mode: host to connect to (default: 127.0.0.1) */
   host?: string;
+  /** Record all DAP traffic, see DapTracer */
+  trace?: DapTraceOptions;
 }
 
 /**
@@ -74,7 +81,8 @@ export interface DapClientEvents {
   process: (event: DebugProtocol.ProcessEvent) => void;
   capabilities: (event: DebugProtocol.CapabilitiesEvent) => void;
   error: (error: Error) => void;
-  adapterExit: (code: number | null) => void;
+  /** reason is set when the adapter went away without being disconnected */
+  adapterExit: (code: number | null, reason?: string) => void;
 }
 
 /**
@@ -88,6 +96,28 @@ interface ChildSession {
   pendingRequests: Map<number, PendingRequest>;
 }
 
+/**
+ * Extract the most detailed error text from a failed response.
+ * Adapters like Delve put a short summary in `message` and the real
+ * diagnostic (e.g. a compile error) in `body.error.format`.
+ */
+function getErrorMessage(response: DebugProtocol.Response): string | undefined {
+  const error = (response as DebugProtocol.ErrorResponse).body?.error;
+  if (!error?.format) {
+    return response.message;
+  }
+
+  const variables = error.variables ?? {};
+  return error.format.replace(/\{(\w+)\}/g, (match, name: string) => variables[name] ?? match);
+}
+
+/** Characters of adapter stderr kept for crash reports */
+const MAX_STDERR_TAIL = 2000;
+/** How long the adapter gets to exit after SIGTERM before its process group is SIGKILLed */
+const KILL_GRACE_MS = 2000;
+/** How long a debuggee gets to handle a terminate request before disconnecting */
+const TERMINATE_TIMEOUT_MS = 2000;
+
 export class DapClient extends EventEmitter {
   private process: ChildProcess | null = null;
   private socket: Socket | null = null;
@@ -97,7 +127,11 @@ export class DapClient extends EventEmitter {
   private capabilities: DebugProtocol.Capabilities | null = null;
   private isConnected: boolean = false;
   private defaultTimeout: number;
-  private connectionMode: 'stdio' | 'tcp';
+  private connectionMode: 'stdio' | 'tcp' | 'connect';
+  private stderrTail: string = '';
+  private disconnecting: boolean = false;
+  private lostReason: string | null = null;
+  private tracer: DapTracer | null;
 
   // Multi-session support for vscode-js-debug
   private tcpPort: number = 0;
@@ -110,6 +144,7 @@ export class DapClient extends EventEmitter {
     this.parser = new DapMessageParser();
     this.defaultTimeout = config.timeout ?? 30000;
     this.connectionMode = config.mode ?? 'stdio';
+    this.tracer = config.trace ? new DapTracer(config.trace) : null;
   }
 
   /**
@@ -122,40 +157,95 @@ export class DapClient extends EventEmitter {
 
     if (this.connectionMode === 'tcp') {
       await this.startTcp();
+    } else if (this.connectionMode === 'connect') {
+      await this.startConnect();
     } else {
       await this.startStdio();
     }
   }
 
   /**
-   * Start in stdio mode (default)
+   * Spawn the adapter process.
+   * On POSIX the adapter leads its own process group so that killing the group
+   * also takes down the debuggee it spawned (e.g. the binary Delve builds).
    */
-  private async startStdio(): Promise<void> {
+  private spawnAdapter(): ChildProcess {
     const spawnOptions: SpawnOptions = {
       stdio: ['pipe', 'pipe', 'pipe'],
       env: { ...process.env, ...this.config.env },
-      cwd: this.config.cwd
+      cwd: this.config.cwd,
+      detached: process.platform !== 'win32'
     };
 
-    this.process = spawn(this.config.command, this.config.args, spawnOptions);
+    return spawn(this.config.command, this.config.args, spawnOptions);
+  }
+
+  /**
+   * Kill the adapter process and everything in its process group
+   */
+  private killProcessTree(signal: NodeJS.Signals = 'SIGTERM'): void {
+    const child = this.process;
+    if (!child?.pid) {
+      return;
+    }
+
+    try {
+      if (process.platform !== 'win32') {
+        process.kill(-child.pid, signal);
+      } else {
+        child.kill(signal);
+      }
+    } catch {
+      // Group already gone - make sure the adapter itself is signalled
+      child.kill(signal);
+    }
+  }
+
+  /**
+   * Signal the adapter's process group to stop, then SIGKILL whatever is
+   * left of it (including the debuggee) once it exits or the grace period ends
+   */
+  private async stopProcessTree(): Promise<void> {
+    const child = this.process;
+    if (!child) {
+      return;
+    }
+
+    if (child.exitCode === null && child.signalCode === null) {
+      const exited = new Promise<void>((resolve) => child.once('exit', () => resolve()));
+      let timer: NodeJS.Timeout | undefined;
+      const grace = new Promise<void>((resolve) => {
+        timer = setTimeout(resolve, KILL_GRACE_MS);
+      });
+      this.killProcessTree('SIGTERM');
+      await Promise.race([exited, grace]);
+      clearTimeout(timer);
+    }
+
+    // The debuggee can outlive the adapter; the group ID still reaches it
+    this.killProcessTree('SIGKILL');
+  }
+
+  /**
+   * Start in stdio mode (default)
+   */
+  private async startStdio(): Promise<void> {
+    this.process = this.spawnAdapter();
 
     // Handle stdout (DAP messages)
     this.process.stdout?.on('data', (data: Buffer) => {
       this.handleData(data);
     });
 
+    // stdout usually closes just before 'exit'; give the exit code a moment
+    // to arrive so the report says how the adapter died
+    this.process.stdout?.on('close', () => {
+      setTimeout(() => this.handleAdapterLost('its output stream closed', null), 100);
+    });
+
     // Handle stderr (adapter logging)
     this.process.stderr?.on('data', (data: Buffer) => {
-      const output: DebugProtocol.OutputEvent = {
-        seq: 0,
-        type: 'event',
-        event: 'output',
-        body: {
-          category: 'stderr',
-          output: data.toString('utf8')
-        }
-      };
-      this.emit('output', output);
+      this.handleStderr(data);
     });
 
     // Handle process errors
@@ -164,10 +254,8 @@ export class DapClient extends EventEmitter {
     });
 
     // Handle process exit
-    this.process.on('exit', (code: number | null) => {
-      this.isConnected = false;
-      this.rejectAllPending(new Error(`Debug adapter exited with code ${code}`));
-      this.emit('adapterExit', code);
+    this.process.on('exit', (code: number | null, signal: NodeJS.Signals | null) => {
+      this.handleAdapterLost(signal ? `killed by ${signal}` : `exit code ${code}`, code);
     });
 
     this.isConnected = true;
@@ -177,13 +265,7 @@ export class DapClient extends EventEmitter {
    * Start in TCP mode - spawn adapter and connect to its TCP port
    */
   private async startTcp(): Promise<void> {
-    const spawnOptions: SpawnOptions = {
-      stdio: ['pipe', 'pipe', 'pipe'],
-      env: { ...process.env, ...this.config.env },
-      cwd: this.config.cwd
-    };
-
-    this.process = spawn(this.config.command, this.config.args, spawnOptions);
+    this.process = this.spawnAdapter();
 
     // Parse port from stderr output
     let port = this.config.port;
@@ -227,13 +309,46 @@ export class DapClient extends EventEmitter {
     }
 
     // Connect to the adapter's TCP port
+    await this.connectSocket(host, port);
+
+    // Handle process exit
+    this.process.on('exit', (code: number | null, signal: NodeJS.Signals | null) => {
+      this.handleAdapterLost(signal ? `killed by ${signal}` : `exit code ${code}`, code);
+      this.socket?.destroy();
+    });
+
+    // Handle stderr for logging
+    this.process.stderr?.on('data', (data: Buffer) => {
+      this.handleStderr(data);
+    });
+
+    this.isConnected = true;
+  }
+
+  /**
+   * Start in connect mode - dial an adapter that is already listening
+   * (e.g. a headless Delve server or `python -m debugpy --listen`)
+   */
+  private async startConnect(): Promise<void> {
+    if (!this.config.port) {
+      throw new Error('Connect mode requires a port');
+    }
+
+    await this.connectSocket(this.config.host ?? '127.0.0.1', this.config.port);
+    this.isConnected = true;
+  }
+
+  /**
+   * Open the DAP socket to an adapter and wire up its handlers
+   */
+  private async connectSocket(host: string, port: number): Promise<void> {
     const socket = await new Promise<Socket>((resolve, reject) => {
       const sock = createConnection({ host, port }, () => {
         resolve(sock);
       });
 
       sock.on('error', (error: Error) => {
-        reject(new Error(`Failed to connect to debug adapter: ${error.message}`));
+        reject(new Error(`Failed to connect to debug adapter at ${host}:${port}: ${error.message}`));
       });
     });
 
@@ -248,39 +363,56 @@ export class DapClient extends EventEmitter {
 
     // Handle socket close
     socket.on('close', () => {
-      this.isConnected = false;
-      this.rejectAllPending(new Error('Debug adapter connection closed'));
-      this.emit('adapterExit', null);
+      this.handleAdapterLost(`connection to ${host}:${port} closed`, null);
     });
 
     // Handle socket errors (after connection)
     socket.on('error', (error: Error) => {
       this.emit('error', error);
     });
+  }
 
-    // Handle process exit
-    this.process.on('exit', (code: number | null) => {
-      this.isConnected = false;
-      this.socket?.destroy();
-      this.rejectAllPending(new Error(`Debug adapter exited with code ${code}`));
-      this.emit('adapterExit', code);
-    });
+  /**
+   * Forward adapter stderr as output and keep its tail for crash reports
+   */
+  private handleStderr(data: Buffer): void {
+    const text = data.toString('utf8');
+    this.stderrTail = (this.stderrTail + text).slice(-MAX_STDERR_TAIL);
 
-    // Handle stderr for logging
-    this.process.stderr?.on('data', (data: Buffer) => {
-      const output: DebugProtocol.OutputEvent = {
-        seq: 0,
-        type: 'event',
-        event: 'output',
-        body: {
-          category: 'stderr',
-          output: data.toString('utf8')
-        }
-      };
-      this.emit('output', output);
-    });
+    const output: DebugProtocol.OutputEvent = {
+      seq: 0,
+      type: 'event',
+      event: 'output',
+      body: {
+        category: 'stderr',
+        output: text
+      }
+    };
+    this.emit('output', output);
+  }
 
-    this.isConnected = true;
+  /**
+   * Fail everything waiting on the adapter once it is gone. Reported once,
+   * whichever of process exit, pipe close or socket close comes first.
+   */
+  private handleAdapterLost(cause: string, code: number | null): void {
+    if (this.lostReason !== null) {
+      return;
+    }
+    this.isConnected = false;
+
+    if (this.disconnecting) {
+      this.lostReason = 'Client disconnected';
+      this.rejectAllPending(new Error(this.lostReason));
+      this.emit('adapterExit', code);
+      return;
+    }
+
+    const stderr = this.stderrTail.trim();
+    this.lostReason =
+      `Debug adapter terminated unexpectedly (${cause})` + (stderr ? `, stderr: ${stderr}` : '');
+    this.rejectAllPending(new Error(this.lostReason));
+    this.emit('adapterExit', code, this.lostReason);
   }
 
   /**
@@ -299,6 +431,7 @@ export class DapClient extends EventEmitter {
    * Handle a parsed DAP message
    */
   private handleMessage(message: DebugProtocol.ProtocolMessage): void {
+    this.tracer?.log('receive', message);
     switch (message.type) {
       case 'response':
         this.handleResponse(message as DebugProtocol.Response);
@@ -330,7 +463,7 @@ export class DapClient extends EventEmitter {
       pending.resolve(response);
     } else {
       pending.reject(
-        new Error(response.message || `Request '${pending.command}' failed`)
+        new Error(getErrorMessage(response) || `Request '${pending.command}' failed`)
       );
     }
   }
@@ -377,6 +510,11 @@ export class DapClient extends EventEmitter {
         this.emit('process', event as DebugProtocol.ProcessEvent);
         break;
       case 'capabilities':
+        // Adapters may announce capabilities that change after initialize
+        this.capabilities = {
+          ...this.capabilities,
+          ...(event as DebugProtocol.CapabilitiesEvent).body.capabilities
+        };
         this.emit('capabilities', event as DebugProtocol.CapabilitiesEvent);
         break;
       default:
@@ -430,8 +568,8 @@ export class DapClient extends EventEmitter {
       return;
     }
 
-    // For TCP mode, create a child session
-    if (this.connectionMode === 'tcp' && this.tcpPort > 0) {
+    // For TCP/connect mode, create a child session
+    if (this.connectionMode !== 'stdio' && this.tcpPort > 0) {
       try {
         await this.createChildSession(targetId, args.configuration);
         this.sendReverseResponse(request, true);
@@ -484,6 +622,10 @@ export class DapClient extends EventEmitter {
       if (this.activeChildSession === childSession) {
         this.activeChildSession = null;
       }
+      this.rejectPending(
+        childSession.pendingRequests,
+        new Error(`Debug target ${targetId} disconnected`)
+      );
     });
 
     socket.on('error', (error: Error) => {
@@ -596,6 +738,7 @@ export class DapClient extends EventEmitter {
    * Send a message to a child session
    */
   private sendToChildSession(session: ChildSession, message: DebugProtocol.ProtocolMessage): void {
+    this.tracer?.log('send', message, session.targetId);
     const encoded = encodeMessage(message);
     session.socket.write(encoded);
   }
@@ -682,6 +825,7 @@ export class DapClient extends EventEmitter {
    * Handle a message from a child session
    */
   private handleChildMessage(session: ChildSession, message: DebugProtocol.ProtocolMessage): void {
+    this.tracer?.log('receive', message, session.targetId);
     switch (message.type) {
       case 'response': {
         const response = message as DebugProtocol.Response;
@@ -692,7 +836,7 @@ export class DapClient extends EventEmitter {
           if (response.success) {
             pending.resolve(response);
           } else {
-            pending.reject(new Error(response.message || 'Request failed'));
+            pending.reject(new Error(getErrorMessage(response) || 'Request failed'));
           }
         }
         break;
@@ -737,8 +881,8 @@ export class DapClient extends EventEmitter {
     args?: object,
     timeout?: number
   ): Promise<T> {
-    if (!this.isConnected || !this.process?.stdin) {
-      throw new Error('DAP client is not connected');
+    if (!this.isConnected) {
+      throw new Error(this.lostReason ?? 'DAP client is not connected');
     }
 
     const seq = this.sequenceNumber++;
@@ -771,9 +915,10 @@ export class DapClient extends EventEmitter {
    * Send a raw DAP message
    */
   private sendRaw(message: DebugProtocol.ProtocolMessage): void {
+    this.tracer?.log('send', message);
     const encoded = encodeMessage(message);
 
-    if (this.connectionMode === 'tcp') {
+    if (this.connectionMode !== 'stdio') {
       if (!this.socket?.writable) {
         throw new Error('Cannot send message: socket is not writable');
       }
@@ -787,14 +932,24 @@ export class DapClient extends EventEmitter {
   }
 
   /**
-   * Reject all pending requests
+   * Reject all pending requests, including those sent to child sessions
    */
   private rejectAllPending(error: Error): void {
-    for (const [seq, pending] of this.pendingRequests) {
+    this.rejectPending(this.pendingRequests, error);
+    for (const child of this.childSessions.values()) {
+      this.rejectPending(child.pendingRequests, error);
+    }
+  }
+
+  /**
+   * Reject and clear one set of pending requests
+   */
+  private rejectPending(requests: Map<number, PendingRequest>, error: Error): void {
+    for (const pending of requests.values()) {
       clearTimeout(pending.timeout);
       pending.reject(error);
     }
-    this.pendingRequests.clear();
+    requests.clear();
   }
 
   // ============================================
@@ -847,8 +1002,23 @@ export class DapClient extends EventEmitter {
    * Use this with adapters that respond to launch after configurationDone.
    */
   launchAsync(args: DebugProtocol.LaunchRequestArguments): void {
-    if (!this.isConnected || !this.process?.stdin) {
-      throw new Error('DAP client is not connected');
+    this.sendAsync('launch', args);
+  }
+
+  /**
+   * Attach to a running program without waiting for response.
+   * Like launch, adapters such as Delve only reply after configurationDone.
+   */
+  attachAsync(args: DebugProtocol.AttachRequestArguments): void {
+    this.sendAsync('attach', args);
+  }
+
+  /**
+   * Send a launch/attach request whose response is awaited via waitForLaunch()
+   */
+  private sendAsync(command: 'launch' | 'attach', args: object): void {
+    if (!this.isConnected) {
+      throw new Error(this.lostReason ?? 'DAP client is not connected');
     }
 
     const seq = this.sequenceNumber++;
@@ -857,7 +1027,7 @@ export class DapClient extends EventEmitter {
     const request: DebugProtocol.Request = {
       seq,
       type: 'request',
-      command: 'launch',
+      command,
       arguments: args
     };
 
@@ -874,7 +1044,7 @@ export class DapClient extends EventEmitter {
         this.pendingLaunchSeq = null;
         this.emit('error', error);
       },
-      command: 'launch',
+      command,
       timeout: setTimeout(() => {}, 0) // No timeout for async launch
     });
 
@@ -953,11 +1123,89 @@ export class DapClient extends EventEmitter {
     );
   }
 
+  /**
+   * Assign a new value to a variable in a container
+   */
+  async setVariable(
+    variablesReference: number,
+    name: string,
+    value: string
+  ): Promise<DebugProtocol.SetVariableResponse['body']> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { variablesReference, name, value };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.SetVariableResponse>('setVariable', args)
+      : await this.sendRequest<DebugProtocol.SetVariableResponse>('setVariable', args);
+    return response.body;
+  }
+
+  /**
+   * Assign a new value to an assignable expression in a frame
+   */
+  async setExpression(
+    expression: string,
+    value: string,
+    frameId?: number
+  ): Promise<DebugProtocol.SetExpressionResponse['body']> {
+    const args = { expression, value, frameId };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.SetExpressionResponse>('setExpression', args)
+      : await this.sendRequest<DebugProtocol.SetExpressionResponse>('setExpression', args);
+    return response.body;
+  }
+
+  /**
+   * Resolve a variable or expression to data breakpoint info
+   */
+  async dataBreakpointInfo(
+    name: string,
+    variablesReference?: number,
+    frameId?: number
+  ): Promise<DebugProtocol.DataBreakpointInfoResponse['body']> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { name, variablesReference, frameId };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.DataBreakpointInfoResponse>('dataBreakpointInfo', args)
+      : await this.sendRequest<DebugProtocol.DataBreakpointInfoResponse>('dataBreakpointInfo', args);
+    return response.body;
+  }
+
+  /**
+   * Set data breakpoints (replaces all existing data breakpoints)
+   */
+  async setDataBreakpoints(
+    breakpoints: DebugProtocol.DataBreakpoint[]
+  ): Promise<DebugProtocol.Breakpoint[]> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { breakpoints };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.SetDataBreakpointsResponse>('setDataBreakpoints', args)
+      : await this.sendRequest<DebugProtocol.SetDataBreakpointsResponse>('setDataBreakpoints', args);
+    return response.body?.breakpoints ?? [];
+  }
+
   /**
    * Set exception breakpoints
    */
   async setExceptionBreakpoints(filters: string[]): Promise<void> {
-    await this.sendRequest('setExceptionBreakpoints', { filters });
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { filters };
+    if (this.activeChildSession) {
+      await this.sendRequestToChild('setExceptionBreakpoints', args);
+    } else {
+      await this.sendRequest('setExceptionBreakpoints', args);
+    }
+  }
+
+  /**
+   * Get details about the exception that caused the current stop
+   */
+  async exceptionInfo(threadId: number): Promise<DebugProtocol.ExceptionInfoResponse['body']> {
+    const args = { threadId };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.ExceptionInfoResponse>('exceptionInfo', args)
+      : await this.sendRequest<DebugProtocol.ExceptionInfoResponse>('exceptionInfo', args);
+    return response.body;
   }
 
   /**
@@ -982,13 +1230,28 @@ export class DapClient extends EventEmitter {
     startFrame?: number,
     levels?: number
   ): Promise<StackFrame[]> {
+    const page = await this.stackTracePage(threadId, startFrame, levels);
+    return page.stackFrames;
+  }
+
+  /**
+   * Get a page of the stack trace along with the total frame count
+   */
+  async stackTracePage(
+    threadId: number,
+    startFrame?: number,
+    levels?: number
+  ): Promise<StackTracePage> {
     // Route to child session if available (for multi-session adapters like vscode-js-debug)
     const args = { threadId, startFrame, levels };
     const response = this.activeChildSession
       ? await this.sendRequestToChild<DebugProtocol.StackTraceResponse>('stackTrace', args)
       : await this.sendRequest<DebugProtocol.StackTraceResponse>('stackTrace', args);
 
-    return (response.body?.stackFrames ?? []).map(convertStackFrame);
+    return {
+      stackFrames: (response.body?.stackFrames ?? []).map(convertStackFrame),
+      totalFrames: response.body?.totalFrames
+    };
   }
 
   /**
@@ -1057,9 +1320,9 @@ export class DapClient extends EventEmitter {
   /**
    * Step to next line (step over)
    */
-  async next(threadId: number): Promise<void> {
+  async next(threadId: number, granularity?: DebugProtocol.SteppingGranularity): Promise<void> {
     // Route to child session if available (for multi-session adapters like vscode-js-debug)
-    const args = { threadId };
+    const args = { threadId, granularity };
     if (this.activeChildSession) {
       await this.sendRequestToChild('next', args);
     } else {
@@ -1070,9 +1333,9 @@ export class DapClient extends EventEmitter {
   /**
    * Step into function
    */
-  async stepIn(threadId: number): Promise<void> {
+  async stepIn(threadId: number, granularity?: DebugProtocol.SteppingGranularity): Promise<void> {
     // Route to child session if available (for multi-session adapters like vscode-js-debug)
-    const args = { threadId };
+    const args = { threadId, granularity };
     if (this.activeChildSession) {
       await this.sendRequestToChild('stepIn', args);
     } else {
@@ -1083,9 +1346,9 @@ export class DapClient extends EventEmitter {
   /**
    * Step out of function
    */
-  async stepOut(threadId: number): Promise<void> {
+  async stepOut(threadId: number, granularity?: DebugProtocol.SteppingGranularity): Promise<void> {
     // Route to child session if available (for multi-session adapters like vscode-js-debug)
-    const args = { threadId };
+    const args = { threadId, granularity };
     if (this.activeChildSession) {
       await this.sendRequestToChild('stepOut', args);
     } else {
@@ -1093,6 +1356,92 @@ export class DapClient extends EventEmitter {
     }
   }
 
+  /**
+   * Disassemble machine instructions around a memory reference
+   */
+  async disassemble(
+    memoryReference: string,
+    instructionOffset: number,
+    instructionCount: number
+  ): Promise<DebugProtocol.DisassembledInstruction[]> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { memoryReference, instructionOffset, instructionCount, resolveSymbols: true };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.DisassembleResponse>('disassemble', args)
+      : await this.sendRequest<DebugProtocol.DisassembleResponse>('disassemble', args);
+    return response.body?.instructions ?? [];
+  }
+
+  /**
+   * Read raw bytes from the debuggee's memory
+   */
+  async readMemory(
+    memoryReference: string,
+    offset: number,
+    count: number
+  ): Promise<DebugProtocol.ReadMemoryResponse['body']> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { memoryReference, offset, count };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.ReadMemoryResponse>('readMemory', args)
+      : await this.sendRequest<DebugProtocol.ReadMemoryResponse>('readMemory', args);
+    return response.body;
+  }
+
+  /**
+   * Ask the adapter how to complete an expression, with column the 1-based
+   * cursor position in text
+   */
+  async completions(
+    text: string,
+    column: number,
+    frameId?: number
+  ): Promise<DebugProtocol.CompletionItem[]> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { text, column, frameId };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.CompletionsResponse>('completions', args)
+      : await this.sendRequest<DebugProtocol.CompletionsResponse>('completions', args);
+    return response.body?.targets ?? [];
+  }
+
+  /**
+   * List the locations execution can jump to for a source line
+   */
+  async gotoTargets(file: string, line: number): Promise<DebugProtocol.GotoTarget[]> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { source: { path: file }, line };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.GotoTargetsResponse>('gotoTargets', args)
+      : await this.sendRequest<DebugProtocol.GotoTargetsResponse>('gotoTargets', args);
+    return response.body?.targets ?? [];
+  }
+
+  /**
+   * Move a thread's program counter to a goto target without running code
+   */
+  async goto(threadId: number, targetId: number): Promise<void> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { threadId, targetId };
+    if (this.activeChildSession) {
+      await this.sendRequestToChild('goto', args);
+    } else {
+      await this.sendRequest('goto', args);
+    }
+  }
+
+  /**
+   * Fetch source text from the adapter, for sources without a readable file
+   */
+  async source(source: DebugProtocol.Source): Promise<string> {
+    // Route to child session if available (for multi-session adapters like vscode-js-debug)
+    const args = { source, sourceReference: source.sourceReference ?? 0 };
+    const response = this.activeChildSession
+      ? await this.sendRequestToChild<DebugProtocol.SourceResponse>('source', args)
+      : await this.sendRequest<DebugProtocol.SourceResponse>('source', args);
+    return response.body.content;
+  }
+
   /**
    * Pause execution
    */
@@ -1115,10 +1464,28 @@ export class DapClient extends EventEmitter {
     }
   }
 
+  /**
+   * Restart the debuggee with the given launch configuration
+   */
+  async restart(args: DebugProtocol.LaunchRequestArguments): Promise<void> {
+    await this.sendRequest('restart', { arguments: args });
+  }
+
   /**
    * Disconnect from the debug adapter
    */
   async disconnect(terminateDebuggee: boolean = true): Promise<void> {
+    this.disconnecting = true;
+
+    // Give the debuggee a chance to shut down cleanly before it is cut off
+    if (terminateDebuggee && this.capabilities?.supportsTerminateRequest) {
+      try {
+        await this.sendRequest('terminate', undefined, TERMINATE_TIMEOUT_MS);
+      } catch {
+        // Already gone, or the adapter does not answer; disconnect anyway
+      }
+    }
+
     try {
       await this.sendRequest('disconnect', { terminateDebuggee }, 5000);
     } catch {
@@ -1128,10 +1495,20 @@ export class DapClient extends EventEmitter {
     this.isConnected = false;
     this.socket?.destroy();
     this.socket = null;
-    this.process?.kill();
+    await this.stopProcessTree();
     this.process = null;
     this.parser.clear();
     this.rejectAllPending(new Error('Client disconnected'));
+    this.tracer?.close();
+  }
+
+  /**
+   * Kill the adapter and debuggee immediately, without talking DAP.
+   * Synchronous so it can run from a process 'exit' handler.
+   */
+  forceKill(): void {
+    this.disconnecting = true;
+    this.killProcessTree('SIGKILL');
   }
 
   /**
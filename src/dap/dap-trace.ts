@@ -0,0 +1,87 @@
+/**
+ * DAP Trace
+ *
+ * Records every DAP message exchanged with an adapter as one JSON line with a
+ * timestamp, so a stalled handshake or a misbehaving adapter can be diagnosed
+ * and attached to a bug report.
+ */
+
+import { createWriteStream, WriteStream } from 'fs';
+import { DebugProtocol } from '@vscode/debugprotocol';
+
+/**
+ * Where and under what name to record DAP traffic
+ */
+export interface DapTraceOptions {
+  /** 'stderr', or a file path to append to */
+  target: string;
+  /** Label written on every line, e.g. the session ID */
+  label?: string;
+}
+
+/**
+ * Read the trace target from MCP_DEBUGGER_TRACE: unset/0/false disables
+ * tracing, 1/true/stderr writes to stderr, anything else is a file path
+ */
+export function getTraceTargetFromEnv(): string | undefined {
+  const raw = process.env.MCP_DEBUGGER_TRACE?.trim();
+  if (!raw || raw === '0' || raw.toLowerCase() === 'false') {
+    return undefined;
+  }
+  if (raw === '1' || raw.toLowerCase() === 'true') {
+    return 'stderr';
+  }
+  return raw;
+}
+
+export class DapTracer {
+  private stream: WriteStream | null = null;
+  private enabled: boolean = true;
+  private label?: string;
+
+  constructor(options: DapTraceOptions) {
+    this.label = options.label;
+    if (options.target !== 'stderr') {
+      this.stream = createWriteStream(options.target, { flags: 'a' });
+      // A bad trace path must not take the debug session down with it
+      this.stream.on('error', (error: Error) => {
+        process.stderr.write(`[mcp-debugger] DAP trace disabled: ${error.message}\n`);
+        this.stream = null;
+        this.enabled = false;
+      });
+    }
+  }
+
+  /**
+   * Record one message. channel names the child session (debug target) it
+   * travelled on, for multi-session adapters like vscode-js-debug.
+   */
+  log(direction: 'send' | 'receive', message: DebugProtocol.ProtocolMessage, channel?: string): void {
+    if (!this.enabled) {
+      return;
+    }
+
+    const line = JSON.stringify({
+      time: new Date().toISOString(),
+      session: this.label,
+      channel,
+      direction,
+      message
+    });
+
+    if (this.stream) {
+      this.stream.write(`${line}\n`);
+    } else {
+      process.stderr.write(`[dap] ${line}\n`);
+    }
+  }
+
+  /**
+   * Flush and close the trace file
+   */
+  close(): void {
+    this.stream?.end();
+    this.stream = null;
+    this.enabled = false;
+  }
+}
@@ -4,3 +4,4 @@
 
 export * from './message-parser.js';
 export * from './dap-client.js';
+export * from './dap-trace.js';
@@ -13,7 +13,7 @@ import {
 } from '@modelcontextprotocol/sdk/types.js';
 import { z } from 'zod';
 import { sessionManager } from './session/session-manager.js';
-import { DebugLanguage, SessionState } from './session/types.js';
+import { DebugLanguage, SessionState, SetBreakpointRequest, VariableLimits } from './session/types.js';
 import { adapterRegistry } from './adapters/index.js';
 
 // Import adapters to register them
@@ -27,14 +27,18 @@ const tools: Tool[] = [
   {
     name: 'create_debug_session',
     description:
-      'Create a new debug session for a specific programming language. Returns a session ID to use with other tools.',
+      'Create a new debug session for a programming language, given explicitly or detected from scriptPath. Returns a session ID to use with other tools.',
     inputSchema: {
       type: 'object',
       properties: {
         language: {
           type: 'string',
-          enum: ['javascript', 'typescript', 'python', 'go', 'rust'],
-          description: 'Programming language to debug'
+          enum: ['javascript', 'typescript', 'node', 'python', 'go', 'rust'],
+          description: 'Programming language to debug (detected from scriptPath if omitted; "node" is an alias for javascript)'
+        },
+        scriptPath: {
+          type: 'string',
+          description: 'Optional entry point used to detect the language by file extension'
         },
         name: {
           type: 'string',
@@ -44,9 +48,35 @@ const tools: Tool[] = [
           type: 'string',
           description:
             'Optional path to the language runtime (e.g., /usr/bin/python3)'
+        },
+        trace: {
+          type: 'boolean',
+          description: 'Log every DAP request, response and event with timestamps to the server\'s stderr, for diagnosing adapter problems (default: MCP_DEBUGGER_TRACE)'
+        },
+        traceFile: {
+          type: 'string',
+          description: 'Append the DAP trace to this file as JSON lines instead of stderr'
+        },
+        limits: {
+          type: 'object',
+          description: 'Caps on inspected values; anything past them is replaced by a "...truncated, N more" marker',
+          properties: {
+            maxStringLength: {
+              type: 'number',
+              description: 'Characters of a value to keep (default 1000, max 100000)'
+            },
+            maxArrayElements: {
+              type: 'number',
+              description: 'Array, slice or list elements to return (default 100, max 1000)'
+            },
+            maxMapEntries: {
+              type: 'number',
+              description: 'Map entries or other named children (struct fields, scope variables) to return (default 100, max 1000)'
+            }
+          }
         }
       },
-      required: ['language']
+      required: []
     }
   },
   {
@@ -67,25 +97,121 @@ const tools: Tool[] = [
         args: {
           type: 'array',
           items: { type: 'string' },
-          description: 'Command line arguments for the script'
+          description: 'Command line arguments for the program (e.g. os.Args[1:] in Go)'
         },
         cwd: {
           type: 'string',
-          description: 'Working directory for script execution'
+          description: 'Working directory for the program; relative paths are resolved against the server\'s directory'
         },
         env: {
           type: 'object',
           additionalProperties: { type: 'string' },
-          description: 'Environment variables'
+          description: 'Environment variables, merged over the inherited environment'
         },
         stopOnEntry: {
           type: 'boolean',
-          description: 'Pause at the first line of code'
+          description: 'Pause at program entry and return that location (default: false, run to the first breakpoint)'
+        },
+        host: {
+          type: 'string',
+          description: 'Host of a running DAP server to launch through, e.g. `dlv dap --listen` in a container (default: 127.0.0.1)'
+        },
+        port: {
+          type: 'number',
+          description: 'Port of a running DAP server. When set, no local adapter is spawned and scriptPath/cwd are resolved on the remote machine'
+        },
+        validateBuild: {
+          type: 'boolean',
+          description: 'Compile Go or Rust sources first (go build / cargo build) and return buildDiagnostics instead of launching a broken build (default: true; prebuilt binaries and interpreted languages are never built). Rust sources must be built to be debugged'
+        },
+        test: {
+          type: 'boolean',
+          description: 'Go only: debug the package\'s tests (like dlv test) instead of its main. scriptPath is the package directory or any file in it; implied by a _test.go scriptPath or testFilter. Test output, including PASS/FAIL and assertion failures, goes to get_output'
+        },
+        testFilter: {
+          type: 'string',
+          description: 'Go only: run only the tests matching this regular expression, like go test -run (e.g. "^TestParse$")'
+        }
+      },
+      required: ['sessionId', 'scriptPath']
+    }
+  },
+  {
+    name: 'validate_build',
+    description: 'Compile the program without debugging it and return structured compile errors (file, line, column, message), so they can be fixed before start_debugging. Go and Rust (cargo) targets are built; prebuilt binaries and interpreted languages report skipped. For Rust, artifact is the binary that start_debugging will launch.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID from create_debug_session'
+        },
+        scriptPath: {
+          type: 'string',
+          description: 'Source file, package directory or Cargo.toml that start_debugging would launch'
+        },
+        cwd: {
+          type: 'string',
+          description: 'Directory to build in (default: the file\'s directory or the package directory)'
+        },
+        env: {
+          type: 'object',
+          additionalProperties: { type: 'string' },
+          description: 'Environment variables for the build (e.g. GOFLAGS, CGO_ENABLED)'
+        },
+        test: {
+          type: 'boolean',
+          description: 'Go only: compile the package\'s tests (go test -c) instead of its main; implied by a _test.go scriptPath'
         }
       },
       required: ['sessionId', 'scriptPath']
     }
   },
+  {
+    name: 'attach_debugger',
+    description:
+      'Attach to an already-running program instead of launching it. Provide either a processId, or a host/port of a running debug server (e.g. dlv --headless, python -m debugpy --listen, node --inspect). Terminating an attached session detaches without killing the program.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID from create_debug_session'
+        },
+        processId: {
+          type: 'number',
+          description: 'Process ID of a local program to attach to'
+        },
+        host: {
+          type: 'string',
+          description: 'Host of a running debug server (default: 127.0.0.1)'
+        },
+        port: {
+          type: 'number',
+          description: 'Port of a running debug server'
+        },
+        cwd: {
+          type: 'string',
+          description: 'Working directory used to resolve source paths'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'restart',
+    description: 'Re-run the program from the start with the same launch arguments. Keeps the session ID and all configured breakpoints. Sessions connected to a remote debug server can only restart if the server supports the DAP restart request.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
   {
     name: 'terminate_session',
     description: 'Terminate a debug session and clean up resources',
@@ -100,9 +226,46 @@ const tools: Tool[] = [
       required: ['sessionId']
     }
   },
+  {
+    name: 'terminate_all',
+    description: 'Terminate every debug session and kill their adapters and debuggees. Use when done debugging so no processes are left holding ports.',
+    inputSchema: {
+      type: 'object',
+      properties: {},
+      required: []
+    }
+  },
+  {
+    name: 'get_status',
+    description: 'Get the lifecycle of a session (created, initializing, configured, running, stopped, terminated, error) and whether it is ready for commands. Includes the location and stop reason when stopped, and the exit code and termination reason (exited, signal, debugger) once terminated.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'get_capabilities',
+    description: 'Get the features supported by the session\'s debug adapter (e.g. supportsConditionalBreakpoints, supportsDataBreakpoints, supportsSetVariable, supportsRestartRequest), as reported by the adapter itself. Available once debugging has started.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
   {
     name: 'list_sessions',
-    description: 'List all active debug sessions',
+    description: 'List all active debug sessions with their language, target, state, age and idle time, to find sessions to terminate. The number of concurrent sessions is capped (MCP_DEBUGGER_MAX_SESSIONS).',
     inputSchema: {
       type: 'object',
       properties: {}
@@ -112,7 +275,7 @@ const tools: Tool[] = [
   // Breakpoints
   {
     name: 'set_breakpoint',
-    description: 'Set a breakpoint at a specific line in a source file. Use dumpFile to create a tracepoint that dumps variables to a file and auto-continues.',
+    description: 'Set a breakpoint at a specific line in a source file. Use dumpFile to create a tracepoint that dumps variables to a file and auto-continues. Fails with the adapter reason if the line cannot be bound (e.g. blank or optimized away); the returned breakpoint.line shows where the adapter actually placed it.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -153,8 +316,52 @@ const tools: Tool[] = [
     }
   },
   {
-    name: 'get_traces',
-    description: 'Get collected traces from tracepoints. Traces are stored in session state and can be queried with filtering and pagination.',
+    name: 'set_breakpoints',
+    description: 'Set several line breakpoints in one call. Entries are grouped by file and sent to the adapter together, which is faster than repeated set_breakpoint calls. Returns results in request order with each breakpoint\'s verified status and bound line.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        breakpoints: {
+          type: 'array',
+          description: 'Breakpoints to add or update',
+          items: {
+            type: 'object',
+            properties: {
+              file: {
+                type: 'string',
+                description: 'Absolute path to the source file'
+              },
+              line: {
+                type: 'number',
+                description: 'Line number (1-based)'
+              },
+              condition: {
+                type: 'string',
+                description: 'Optional conditional expression'
+              },
+              hitCondition: {
+                type: 'string',
+                description: 'Optional hit count condition (e.g., ">5", "==10")'
+              },
+              logMessage: {
+                type: 'string',
+                description: 'Optional message to log instead of stopping, with {expression} placeholders'
+              }
+            },
+            required: ['file', 'line']
+          }
+        }
+      },
+      required: ['sessionId', 'breakpoints']
+    }
+  },
+  {
+    name: 'set_logpoint',
+    description: 'Set a logpoint: each time the line is reached the adapter prints the message and keeps running, instead of pausing. Embed expressions in braces, e.g. "total={total} i={i}". The lines appear in get_output alongside the program\'s own output.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -164,45 +371,57 @@ const tools: Tool[] = [
         },
         file: {
           type: 'string',
-          description: 'Filter by file path (partial match supported)'
+          description: 'Absolute path to the source file'
         },
         line: {
           type: 'number',
-          description: 'Filter by line number'
+          description: 'Line number (1-based)'
         },
-        function: {
+        message: {
           type: 'string',
-          description: 'Filter by function name (partial match)'
+          description: 'Message template; {expression} placeholders are evaluated in the frame at that line'
         },
-        limit: {
-          type: 'number',
-          description: 'Max number of traces to return (default: 100)'
+        condition: {
+          type: 'string',
+          description: 'Optional conditional expression (only logs when true)'
         },
-        offset: {
-          type: 'number',
-          description: 'Number of traces to skip (for pagination)'
+        hitCondition: {
+          type: 'string',
+          description: 'Optional hit count condition (e.g., ">5", "==10")'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'file', 'line', 'message']
     }
   },
   {
-    name: 'clear_traces',
-    description: 'Clear all collected traces from the session',
+    name: 'set_function_breakpoint',
+    description: 'Set a breakpoint on entry to a function by name (e.g., "calculate" or "main.calculate"), without knowing its line number. Returns the file and line the adapter bound it to.',
     inputSchema: {
       type: 'object',
       properties: {
         sessionId: {
           type: 'string',
           description: 'Debug session ID'
+        },
+        name: {
+          type: 'string',
+          description: 'Function name, optionally qualified by package/module'
+        },
+        condition: {
+          type: 'string',
+          description: 'Optional conditional expression (breakpoint only triggers when true)'
+        },
+        hitCondition: {
+          type: 'string',
+          description: 'Optional hit count condition (e.g., ">5", "==10")'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'name']
     }
   },
   {
-    name: 'remove_breakpoint',
-    description: 'Remove a breakpoint from a source file',
+    name: 'set_watchpoint',
+    description: 'Stop when a variable changes (or is read), instead of stepping manually. The program must be paused so the variable can be resolved. Not every adapter supports data breakpoints; unsupported adapters return an explicit error.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -210,67 +429,82 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        file: {
+        name: {
           type: 'string',
-          description: 'Absolute path to the source file'
+          description: 'Variable name to watch (e.g., "total")'
         },
-        line: {
+        accessType: {
+          type: 'string',
+          enum: ['write', 'read', 'readWrite'],
+          description: 'Access that triggers the stop (default: write)'
+        },
+        frameId: {
           type: 'number',
-          description: 'Line number of the breakpoint to remove'
+          description: 'Optional stack frame ID to resolve the variable in (defaults to current frame)'
+        },
+        condition: {
+          type: 'string',
+          description: 'Optional conditional expression'
+        },
+        hitCondition: {
+          type: 'string',
+          description: 'Optional hit count condition'
         }
       },
-      required: ['sessionId', 'file', 'line']
+      required: ['sessionId', 'name']
     }
   },
   {
-    name: 'list_breakpoints',
-    description: 'List all breakpoints in the session',
+    name: 'get_traces',
+    description: 'Get collected traces from tracepoints. Traces are stored in session state and can be queried with filtering and pagination.',
     inputSchema: {
       type: 'object',
       properties: {
         sessionId: {
           type: 'string',
           description: 'Debug session ID'
+        },
+        file: {
+          type: 'string',
+          description: 'Filter by file path (partial match supported)'
+        },
+        line: {
+          type: 'number',
+          description: 'Filter by line number'
+        },
+        function: {
+          type: 'string',
+          description: 'Filter by function name (partial match)'
+        },
+        limit: {
+          type: 'number',
+          description: 'Max number of traces to return (default: 100)'
+        },
+        offset: {
+          type: 'number',
+          description: 'Number of traces to skip (for pagination)'
         }
       },
       required: ['sessionId']
     }
   },
-
-  // Execution Control
   {
-    name: 'continue',
-    description: 'Continue execution until the next breakpoint or program end. Use waitForBreakpoint to block until a breakpoint is hit and return variables. Use collectHits to run through multiple breakpoint hits, collecting variables at each, then return all traces.',
+    name: 'clear_traces',
+    description: 'Clear all collected traces from the session',
     inputSchema: {
       type: 'object',
       properties: {
         sessionId: {
           type: 'string',
           description: 'Debug session ID'
-        },
-        threadId: {
-          type: 'number',
-          description: 'Optional thread ID (defaults to current thread)'
-        },
-        waitForBreakpoint: {
-          type: 'boolean',
-          description: 'Wait for breakpoint hit and return variables (default: false)'
-        },
-        timeout: {
-          type: 'number',
-          description: 'Timeout in ms when waiting for breakpoint (default: 30000)'
-        },
-        collectHits: {
-          type: 'number',
-          description: 'Collect this many breakpoint hits before returning. At each hit, variables are captured and execution auto-continues. Returns all traces when done.'
         }
       },
       required: ['sessionId']
     }
   },
   {
-    name: 'pause',
-    description: 'Pause program execution',
+    name: 'get_output',
+    description: 'Get output printed by the debugged program (stdout/stderr/console), tagged by category. Output is buffered per session, so nothing printed between a continue and the next stop is lost.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -278,17 +512,22 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        threadId: {
-          type: 'number',
-          description: 'Optional thread ID'
+        follow: {
+          type: 'boolean',
+          description: 'Only return output produced since the previous get_output call (default: true). Set false to return the whole buffer.'
+        },
+        category: {
+          type: 'string',
+          enum: ['stdout', 'stderr', 'console', 'important'],
+          description: 'Optional category filter. Output of other categories stays unread.'
         }
       },
       required: ['sessionId']
     }
   },
   {
-    name: 'step_in',
-    description: 'Step into the next function call',
+    name: 'set_exception_breakpoints',
+    description: 'Choose which exceptions/panics pause execution, replacing previous filters. Filter IDs depend on the adapter (e.g. "raised"/"uncaught" for Python, "all"/"uncaught" for JavaScript); the result lists availableFilters. Delve always stops on unrecovered Go panics. Exception stops report the exception message in stopReason.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -296,17 +535,18 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        threadId: {
-          type: 'number',
-          description: 'Optional thread ID'
+        filters: {
+          type: 'array',
+          items: { type: 'string' },
+          description: 'Exception filter IDs to enable (empty to disable all)'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'filters']
     }
   },
   {
-    name: 'step_over',
-    description: 'Step over to the next line (execute function calls without entering)',
+    name: 'remove_breakpoint',
+    description: 'Remove a single breakpoint, by file and line or by the breakpoint ID returned from set_breakpoint. Returns the remaining breakpoints.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -314,17 +554,25 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        threadId: {
+        file: {
+          type: 'string',
+          description: 'Absolute path to the source file'
+        },
+        line: {
           type: 'number',
-          description: 'Optional thread ID'
+          description: 'Line number of the breakpoint to remove'
+        },
+        breakpointId: {
+          type: 'number',
+          description: 'Breakpoint ID (alternative to file and line)'
         }
       },
       required: ['sessionId']
     }
   },
   {
-    name: 'step_out',
-    description: 'Step out of the current function',
+    name: 'clear_breakpoints',
+    description: 'Remove all line breakpoints, or only those in one file. Returns the remaining breakpoints.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -332,24 +580,294 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        threadId: {
-          type: 'number',
-          description: 'Optional thread ID'
+        file: {
+          type: 'string',
+          description: 'Only clear breakpoints in this file'
         }
       },
       required: ['sessionId']
     }
   },
   {
-    name: 'step_and_trace',
-    description: 'Step through code N times, collecting variables at each step. Returns all traces or writes to a file. Useful for tracing execution flow.',
+    name: 'list_breakpoints',
+    description: 'List all breakpoints in the session',
     inputSchema: {
       type: 'object',
       properties: {
         sessionId: {
           type: 'string',
           description: 'Debug session ID'
-        },
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+
+  // Execution Control
+  {
+    name: 'continue',
+    description: 'Continue execution until the next breakpoint or program end. Use waitForBreakpoint to block until a breakpoint is hit and return variables. Use collectHits to run through multiple breakpoint hits, collecting variables at each, then return all traces. The result status is "stopped", "exited" (with exitCode) or "running" if nothing happened within the timeout.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID (defaults to current thread)'
+        },
+        waitForBreakpoint: {
+          type: 'boolean',
+          description: 'Wait for breakpoint hit and return variables (default: false)'
+        },
+        timeout: {
+          type: 'number',
+          description: 'Max time in ms to wait for a stop or exit; setting it implies waiting (default: 30000)'
+        },
+        collectHits: {
+          type: 'number',
+          description: 'Collect this many breakpoint hits before returning. At each hit, variables are captured and execution auto-continues. Returns all traces when done.'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'continue_until',
+    description: 'Continue repeatedly until an expression is true, e.g. "total > 100" or "len(items) == 0". At each breakpoint stop the expression is evaluated in the stopped frame; if it is false execution continues automatically. Returns when it is true, when it fails to evaluate (paused at that stop), on any non-breakpoint stop (exception, pause), when the program exits, or after maxIterations stops. Uses your existing breakpoints, so set at least one where the expression should be checked.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        expression: {
+          type: 'string',
+          description: 'Condition to wait for, in the debugged language'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID to continue (defaults to current thread)'
+        },
+        maxIterations: {
+          type: 'number',
+          description: 'Give up after this many breakpoint stops, staying paused at the last one (default: 100)'
+        },
+        timeout: {
+          type: 'number',
+          description: 'Max total time in ms (default: 30000)'
+        }
+      },
+      required: ['sessionId', 'expression']
+    }
+  },
+  {
+    name: 'wait_for_stop',
+    description: 'Wait for a running program to stop or exit, e.g. after continue returned status "running". Returns immediately if it already stopped or exited.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        timeout: {
+          type: 'number',
+          description: 'Max time to wait in milliseconds (default 30000)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'run_to_line',
+    description: 'Run to a line (run-to-cursor): sets a temporary breakpoint, continues, waits for the stop and removes the temporary breakpoint. Existing breakpoints are untouched and may stop execution first. Reports if the program exits before reaching the line.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        file: {
+          type: 'string',
+          description: 'Absolute path to the source file'
+        },
+        line: {
+          type: 'number',
+          description: 'Line number to run to'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        },
+        timeout: {
+          type: 'number',
+          description: 'Max time to wait in milliseconds (default 30000)'
+        }
+      },
+      required: ['sessionId', 'file', 'line']
+    }
+  },
+  {
+    name: 'goto_targets',
+    description: 'List the locations on a source line that the program counter can be moved to with goto. Requires adapter support (e.g. debugpy, CodeLLDB); Delve does not support it.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        file: {
+          type: 'string',
+          description: 'Absolute path to the source file'
+        },
+        line: {
+          type: 'number',
+          description: 'Line number to jump to'
+        }
+      },
+      required: ['sessionId', 'file', 'line']
+    }
+  },
+  {
+    name: 'goto',
+    description: 'Set next statement: move the paused thread\'s program counter to a line without running the code in between, to skip a block or re-execute a line. Returns the new location to confirm the jump. Fails with a clear message when the adapter does not support it (e.g. Delve).',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        file: {
+          type: 'string',
+          description: 'Absolute path to the source file (the first goto target on the line is used)'
+        },
+        line: {
+          type: 'number',
+          description: 'Line number to jump to'
+        },
+        targetId: {
+          type: 'number',
+          description: 'Target ID from goto_targets, instead of file and line'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID (default: the stopped thread)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'pause',
+    description: 'Interrupt a running program (e.g. stuck in a loop) and return where it stopped, with the local variables',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        },
+        timeout: {
+          type: 'number',
+          description: 'Max time to wait for the program to stop in milliseconds (default 5000)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'step_instruction',
+    description: 'Step a single machine instruction (for optimized code or crashes without a clean source line). Only available on adapters that support stepping granularity.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'step_in',
+    description: 'Step into the next function call',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'step_over',
+    description: 'Step over to the next line (execute function calls without entering)',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'step_out',
+    description: 'Step out of the current function',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'step_and_trace',
+    description: 'Step through code N times, collecting variables at each step. Returns all traces or writes to a file. Useful for tracing execution flow.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
         count: {
           type: 'number',
           description: 'Number of steps to take (default: 100)'
@@ -363,19 +881,215 @@ const tools: Tool[] = [
           enum: ['in', 'over', 'out'],
           description: 'Type of step: in (step into functions), over (step over), out (step out). Default: over'
         },
-        dumpFile: {
-          type: 'string',
-          description: 'If set, write traces to this file (JSONL format) instead of returning in response'
+        dumpFile: {
+          type: 'string',
+          description: 'If set, write traces to this file (JSONL format) instead of returning in response'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+
+  // Inspection
+  {
+    name: 'get_stack_trace',
+    description: 'Get the current call stack. Each frame has name, file, line and an id that can be passed as frameId to get_variables and evaluate_expression.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Optional thread ID'
+        },
+        startFrame: {
+          type: 'number',
+          description: 'Index of the first frame to return (default 0)'
+        },
+        levels: {
+          type: 'number',
+          description: 'Maximum number of frames to return (default: all)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'select_frame',
+    description: 'Select the stack frame that get_scopes, get_variables, evaluate_expression, set_variable and get_source_context use when no frameId is given. Pass frameId, a level (0 = innermost) or direction "up" (toward the caller) / "down". Reset to the top frame whenever the program stops.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Frame ID from get_stack_trace'
+        },
+        level: {
+          type: 'number',
+          description: 'Stack level to select, 0 being the innermost frame'
+        },
+        direction: {
+          type: 'string',
+          enum: ['up', 'down'],
+          description: 'Move one frame toward the caller (up) or back toward the innermost frame (down)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread whose stack to select from (default: the selected or stopped thread)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'get_scopes',
+    description: 'List the scopes of a stack frame (Locals, Arguments, Globals, ...), each with a variablesReference to pass to expand_variable. Scopes marked expensive (e.g. Globals) can be slow to expand.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Stack frame ID (default: top frame)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread (goroutine) whose top frame to use when frameId is omitted'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'get_variables',
+    description: 'Get variables in the current scope as a structured tree. Each variable has name, type, value, hasChildren and a variablesReference that can be passed to expand_variable.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Optional stack frame ID (defaults to top frame)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread (goroutine) whose top frame to use when frameId is omitted'
+        },
+        scope: {
+          type: 'string',
+          enum: ['local', 'global', 'closure'],
+          description: 'Optional scope filter'
+        },
+        depth: {
+          type: 'number',
+          description: 'Levels of children to expand inline (default 0, max 5)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
+  {
+    name: 'expand_variable',
+    description: 'Expand a complex variable to see its properties/children. Use this to drill into a specific struct field, slice element or map entry.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        variablesReference: {
+          type: 'number',
+          description: 'Variables reference from get_variables result'
+        },
+        depth: {
+          type: 'number',
+          description: 'Levels of children to expand (default 1, max 5)'
+        }
+      },
+      required: ['sessionId', 'variablesReference']
+    }
+  },
+  {
+    name: 'evaluate_expression',
+    description:
+      'Evaluate an expression (e.g. "x + y", "len(items)") in a stack frame of a paused session. ' +
+      'Returns the result value and type, or the adapter\'s error message if evaluation fails. ' +
+      'Expressions that call functions may have side effects on the debuggee.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        expression: {
+          type: 'string',
+          description: 'Expression to evaluate'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Stack frame ID to evaluate in (default: top frame)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread (goroutine) whose top frame to use when frameId is omitted'
+        },
+        context: {
+          type: 'string',
+          enum: ['watch', 'repl', 'hover'],
+          description: 'Evaluation context (default: repl)'
+        }
+      },
+      required: ['sessionId', 'expression']
+    }
+  },
+  {
+    name: 'inspect_expression',
+    description: 'Evaluate an expression and return its value with children expanded into a tree (struct fields, slice elements, map entries) in one call, instead of evaluate_expression followed by repeated expand_variable. Long strings and large collections are truncated with a "...truncated, N more" marker.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        expression: {
+          type: 'string',
+          description: 'Expression to inspect, e.g. a variable name or "cfg.Servers[0]"'
+        },
+        depth: {
+          type: 'number',
+          description: 'Levels of children to expand (default 2, max 5)'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Stack frame ID to evaluate in (default: selected frame)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread (goroutine) whose top frame to use when frameId is omitted'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'expression']
     }
   },
-
-  // Inspection
   {
-    name: 'get_stack_trace',
-    description: 'Get the current call stack',
+    name: 'completions',
+    description: 'Suggest completions for a partial expression (e.g. "user." lists the fields and methods of user), to check names before calling evaluate_expression. Not all adapters support this.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -383,17 +1097,29 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
+        text: {
+          type: 'string',
+          description: 'Partial expression to complete'
+        },
+        column: {
+          type: 'number',
+          description: 'Cursor position in text, 1-based (default: end of text)'
+        },
+        frameId: {
+          type: 'number',
+          description: 'Frame to complete in (default: selected frame)'
+        },
         threadId: {
           type: 'number',
-          description: 'Optional thread ID'
+          description: 'Thread whose top frame to complete in, when frameId is not given'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'text']
     }
   },
   {
-    name: 'get_variables',
-    description: 'Get variables in the current scope',
+    name: 'set_variable',
+    description: 'Change the value of a variable while paused (e.g. set total to 100), then continue to test a hypothesis. Returns the value as the adapter parsed it, or the adapter error if the assignment is rejected.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -401,22 +1127,29 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
+        name: {
+          type: 'string',
+          description: 'Variable name (or assignable expression, where supported)'
+        },
+        value: {
+          type: 'string',
+          description: 'New value as source text in the program\'s language, e.g. "100"'
+        },
         frameId: {
           type: 'number',
-          description: 'Optional stack frame ID (defaults to top frame)'
+          description: 'Stack frame ID to resolve the variable in (default: top frame)'
         },
-        scope: {
-          type: 'string',
-          enum: ['local', 'global', 'closure'],
-          description: 'Optional scope filter'
+        variablesReference: {
+          type: 'number',
+          description: 'Reference of the containing scope or struct, to set a field of an expanded variable'
         }
       },
-      required: ['sessionId']
+      required: ['sessionId', 'name', 'value']
     }
   },
   {
-    name: 'expand_variable',
-    description: 'Expand a complex variable to see its properties/children',
+    name: 'disassemble',
+    description: 'Disassemble machine instructions around the current instruction of a frame (or a memory reference), with the source file and line of each instruction where known. Only available on adapters that support disassembly.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -424,17 +1157,29 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        variablesReference: {
+        frameId: {
           type: 'number',
-          description: 'Variables reference from get_variables result'
+          description: 'Stack frame whose instruction pointer to disassemble around (default: top frame)'
+        },
+        memoryReference: {
+          type: 'string',
+          description: 'Address to disassemble from instead of a frame\'s instruction pointer'
+        },
+        instructionOffset: {
+          type: 'number',
+          description: 'Offset in instructions from the address (default: centers the window)'
+        },
+        instructionCount: {
+          type: 'number',
+          description: 'Number of instructions to return (default 20)'
         }
       },
-      required: ['sessionId', 'variablesReference']
+      required: ['sessionId']
     }
   },
   {
-    name: 'evaluate_expression',
-    description: 'Evaluate an expression in the current context',
+    name: 'read_memory',
+    description: 'Read raw bytes from the debugged program\'s memory, e.g. the backing array of a slice or a network buffer whose pretty-printed value hides the bytes. Take the memoryReference from a variable (get_variables/evaluate_expression) or an address. Only available on adapters that support reading memory.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -442,26 +1187,30 @@ const tools: Tool[] = [
           type: 'string',
           description: 'Debug session ID'
         },
-        expression: {
+        memoryReference: {
           type: 'string',
-          description: 'Expression to evaluate'
+          description: 'Memory reference of a variable, or an address such as 0xc000012345'
         },
-        frameId: {
+        offset: {
           type: 'number',
-          description: 'Optional stack frame ID for context'
+          description: 'Byte offset from the reference (may be negative; default 0)'
         },
-        context: {
+        count: {
+          type: 'number',
+          description: 'Number of bytes to read (default 64, max 4096)'
+        },
+        format: {
           type: 'string',
-          enum: ['watch', 'repl', 'hover'],
-          description: 'Evaluation context'
+          enum: ['hex', 'base64', 'both'],
+          description: 'Encoding of the returned bytes (default: hex)'
         }
       },
-      required: ['sessionId', 'expression']
+      required: ['sessionId', 'memoryReference']
     }
   },
   {
     name: 'get_source_context',
-    description: 'Get source code around the current execution point',
+    description: 'Get source code around the current execution point, a stack frame, or a file and line, with the current line marked. Source the adapter holds in memory (eval\'d code, files only on a remote machine) is fetched from the adapter; origin says where the text came from.',
     inputSchema: {
       type: 'object',
       properties: {
@@ -477,6 +1226,14 @@ const tools: Tool[] = [
           type: 'number',
           description: 'Optional center line number'
         },
+        frameId: {
+          type: 'number',
+          description: 'Show the source of this stack frame instead of the top frame (from get_stack_trace)'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread whose stack frameId belongs to (default: the thread that stopped)'
+        },
         linesContext: {
           type: 'number',
           description: 'Lines of context above and below (default 5)'
@@ -485,15 +1242,53 @@ const tools: Tool[] = [
       required: ['sessionId']
     }
   },
+  {
+    name: 'where',
+    description: 'Get the full picture after a stop in one call: stop reason, top stack frames, top-frame locals and the source around the current line. Use frames/maxVariables/linesContext to trade detail for size, e.g. frames: 1, maxVariables: 0 for a cheap overview.',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        sessionId: {
+          type: 'string',
+          description: 'Debug session ID'
+        },
+        threadId: {
+          type: 'number',
+          description: 'Thread to describe (default: the thread that stopped)'
+        },
+        frames: {
+          type: 'number',
+          description: 'Number of stack frames from the top (default 5)'
+        },
+        maxVariables: {
+          type: 'number',
+          description: 'Maximum top-frame locals to include; 0 omits them (default 50)'
+        },
+        depth: {
+          type: 'number',
+          description: 'Levels of nested children to expand for each local (default 0)'
+        },
+        linesContext: {
+          type: 'number',
+          description: 'Source lines above and below the current line; -1 omits source (default 5)'
+        }
+      },
+      required: ['sessionId']
+    }
+  },
   {
     name: 'get_threads',
-    description: 'Get all threads in the debugged program',
+    description: 'Get all threads in the debugged program (goroutines for Go). Pass a thread ID as threadId to get_stack_trace, get_variables or evaluate_expression to inspect that thread, e.g. to find a goroutine blocked on a channel.',
     inputSchema: {
       type: 'object',
       properties: {
         sessionId: {
           type: 'string',
           description: 'Debug session ID'
+        },
+        withLocation: {
+          type: 'boolean',
+          description: 'Include the top stack frame of each thread (only while paused; default false)'
         }
       },
       required: ['sessionId']
@@ -563,14 +1358,22 @@ async function handleToolCall(
   switch (name) {
     // Session Management
     case 'create_debug_session': {
-      const language = args.language as string;
+      const language = args.language as string | undefined;
+      const scriptPath = args.scriptPath as string | undefined;
       const sessionName = args.name as string | undefined;
       const executablePath = args.executablePath as string | undefined;
+      const trace = args.trace as boolean | undefined;
+      const traceFile = args.traceFile as string | undefined;
+      const limits = args.limits as VariableLimits | undefined;
 
       const session = await sessionManager.createSession({
-        language: language as DebugLanguage,
+        language: language as DebugLanguage | undefined,
+        scriptPath,
         name: sessionName,
-        executablePath
+        executablePath,
+        trace,
+        traceFile,
+        limits
       });
 
       return {
@@ -588,31 +1391,93 @@ async function handleToolCall(
       const cwd = args.cwd as string | undefined;
       const env = args.env as Record<string, string> | undefined;
       const stopOnEntry = args.stopOnEntry as boolean | undefined;
+      const host = args.host as string | undefined;
+      const port = args.port as number | undefined;
+      const validateBuild = args.validateBuild as boolean | undefined;
+      const test = args.test as boolean | undefined;
+      const testFilter = args.testFilter as string | undefined;
 
       return sessionManager.startDebugging(sessionId, {
         scriptPath,
         args: scriptArgs,
         cwd,
         env,
-        stopOnEntry
+        stopOnEntry,
+        host,
+        port,
+        validateBuild,
+        test,
+        testFilter
+      });
+    }
+
+    case 'validate_build': {
+      const sessionId = args.sessionId as string;
+      const scriptPath = args.scriptPath as string;
+      const cwd = args.cwd as string | undefined;
+      const env = args.env as Record<string, string> | undefined;
+      const test = args.test as boolean | undefined;
+      return sessionManager.validateBuild(sessionId, { scriptPath, cwd, env, test });
+    }
+
+    case 'attach_debugger': {
+      const sessionId = args.sessionId as string;
+      const processId = args.processId as number | undefined;
+      const host = args.host as string | undefined;
+      const port = args.port as number | undefined;
+      const cwd = args.cwd as string | undefined;
+
+      return sessionManager.attachDebugging(sessionId, {
+        processId,
+        host,
+        port,
+        cwd
       });
     }
 
+    case 'restart': {
+      const sessionId = args.sessionId as string;
+      return sessionManager.restartSession(sessionId);
+    }
+
     case 'terminate_session': {
       const sessionId = args.sessionId as string;
       return sessionManager.terminateSession(sessionId);
     }
 
+    case 'terminate_all': {
+      return sessionManager.terminateAll();
+    }
+
+    case 'get_status': {
+      const sessionId = args.sessionId as string;
+      return sessionManager.getStatus(sessionId);
+    }
+
+    case 'get_capabilities': {
+      const sessionId = args.sessionId as string;
+      return sessionManager.getCapabilities(sessionId);
+    }
+
     case 'list_sessions': {
       const sessions = sessionManager.listSessions();
+      const now = Date.now();
+      const maxSessions = sessionManager.getMaxSessions();
       return {
         sessions: sessions.map((s) => ({
           sessionId: s.id,
           name: s.name,
           language: s.language,
           state: s.state,
-          scriptPath: s.scriptPath
-        }))
+          mode: s.mode,
+          scriptPath: s.scriptPath,
+          processId: s.processId,
+          createdAt: s.createdAt.toISOString(),
+          ageSeconds: Math.round((now - s.createdAt.getTime()) / 1000),
+          idleSeconds: Math.round(s.idleMs / 1000)
+        })),
+        count: sessions.length,
+        maxSessions: maxSessions > 0 ? maxSessions : undefined
       };
     }
 
@@ -638,6 +1503,68 @@ async function handleToolCall(
       });
     }
 
+    case 'set_breakpoints': {
+      const sessionId = args.sessionId as string;
+      const breakpoints = (args.breakpoints as SetBreakpointRequest[] | undefined) ?? [];
+      return sessionManager.setBreakpoints(
+        sessionId,
+        breakpoints.map(({ file, line, condition, hitCondition, logMessage }) => ({
+          file,
+          line,
+          condition,
+          hitCondition,
+          logMessage
+        }))
+      );
+    }
+
+    case 'set_logpoint': {
+      const sessionId = args.sessionId as string;
+      const file = args.file as string;
+      const line = args.line as number;
+      const message = args.message as string;
+      const condition = args.condition as string | undefined;
+      const hitCondition = args.hitCondition as string | undefined;
+
+      return sessionManager.setLogpoint(sessionId, {
+        file,
+        line,
+        message,
+        condition,
+        hitCondition
+      });
+    }
+
+    case 'set_function_breakpoint': {
+      const sessionId = args.sessionId as string;
+      const functionName = args.name as string;
+      const condition = args.condition as string | undefined;
+      const hitCondition = args.hitCondition as string | undefined;
+
+      return sessionManager.setFunctionBreakpoint(sessionId, {
+        name: functionName,
+        condition,
+        hitCondition
+      });
+    }
+
+    case 'set_watchpoint': {
+      const sessionId = args.sessionId as string;
+      const variableName = args.name as string;
+      const accessType = args.accessType as 'write' | 'read' | 'readWrite' | undefined;
+      const frameId = args.frameId as number | undefined;
+      const condition = args.condition as string | undefined;
+      const hitCondition = args.hitCondition as string | undefined;
+
+      return sessionManager.setWatchpoint(sessionId, {
+        name: variableName,
+        accessType,
+        frameId,
+        condition,
+        hitCondition
+      });
+    }
+
     case 'get_traces': {
       const sessionId = args.sessionId as string;
       const file = args.file as string | undefined;
@@ -654,18 +1581,40 @@ async function handleToolCall(
       return sessionManager.clearTraces(sessionId);
     }
 
+    case 'get_output': {
+      const sessionId = args.sessionId as string;
+      const follow = args.follow as boolean | undefined;
+      const category = args.category as 'stdout' | 'stderr' | 'console' | 'important' | undefined;
+      return sessionManager.getOutput(sessionId, { follow, category });
+    }
+
+    case 'set_exception_breakpoints': {
+      const sessionId = args.sessionId as string;
+      const filters = args.filters as string[];
+      return sessionManager.setExceptionBreakpoints(sessionId, filters);
+    }
+
     case 'remove_breakpoint': {
       const sessionId = args.sessionId as string;
-      const file = args.file as string;
-      const line = args.line as number;
+      const file = args.file as string | undefined;
+      const line = args.line as number | undefined;
+      const breakpointId = args.breakpointId as number | undefined;
+
+      return sessionManager.removeBreakpoint(sessionId, { file, line, breakpointId });
+    }
 
-      return sessionManager.removeBreakpoint(sessionId, file, line);
+    case 'clear_breakpoints': {
+      const sessionId = args.sessionId as string;
+      const file = args.file as string | undefined;
+      return sessionManager.clearBreakpoints(sessionId, file);
     }
 
     case 'list_breakpoints': {
       const sessionId = args.sessionId as string;
       const breakpoints = sessionManager.listBreakpoints(sessionId);
-      return { breakpoints };
+      const functionBreakpoints = sessionManager.listFunctionBreakpoints(sessionId);
+      const watchpoints = sessionManager.listWatchpoints(sessionId);
+      return { breakpoints, functionBreakpoints, watchpoints };
     }
 
     // Execution Control
@@ -678,10 +1627,61 @@ async function handleToolCall(
       return sessionManager.continue(sessionId, threadId, { waitForBreakpoint, timeout, collectHits });
     }
 
+    case 'continue_until': {
+      const sessionId = args.sessionId as string;
+      const expression = args.expression as string;
+      const threadId = args.threadId as number | undefined;
+      const maxIterations = args.maxIterations as number | undefined;
+      const timeout = args.timeout as number | undefined;
+      return sessionManager.continueUntil(sessionId, expression, {
+        threadId,
+        maxIterations,
+        timeout
+      });
+    }
+
+    case 'wait_for_stop': {
+      const sessionId = args.sessionId as string;
+      const timeout = args.timeout as number | undefined;
+      return sessionManager.waitForStop(sessionId, timeout);
+    }
+
+    case 'run_to_line': {
+      const sessionId = args.sessionId as string;
+      const file = args.file as string;
+      const line = args.line as number;
+      const threadId = args.threadId as number | undefined;
+      const timeout = args.timeout as number | undefined;
+      return sessionManager.runToLine(sessionId, file, line, { threadId, timeout });
+    }
+
+    case 'goto_targets': {
+      const sessionId = args.sessionId as string;
+      const file = args.file as string;
+      const line = args.line as number;
+      return sessionManager.getGotoTargets(sessionId, file, line);
+    }
+
+    case 'goto': {
+      const sessionId = args.sessionId as string;
+      const file = args.file as string | undefined;
+      const line = args.line as number | undefined;
+      const targetId = args.targetId as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      return sessionManager.goto(sessionId, { file, line, targetId, threadId });
+    }
+
     case 'pause': {
       const sessionId = args.sessionId as string;
       const threadId = args.threadId as number | undefined;
-      return sessionManager.pause(sessionId, threadId);
+      const timeout = args.timeout as number | undefined;
+      return sessionManager.pause(sessionId, threadId, timeout);
+    }
+
+    case 'step_instruction': {
+      const sessionId = args.sessionId as string;
+      const threadId = args.threadId as number | undefined;
+      return sessionManager.stepInstruction(sessionId, threadId);
     }
 
     case 'step_in': {
@@ -715,24 +1715,58 @@ async function handleToolCall(
     case 'get_stack_trace': {
       const sessionId = args.sessionId as string;
       const threadId = args.threadId as number | undefined;
-      const stackFrames = await sessionManager.getStackTrace(sessionId, threadId);
-      return { stackFrames, count: stackFrames.length };
+      const startFrame = args.startFrame as number | undefined;
+      const levels = args.levels as number | undefined;
+      const { stackFrames, totalFrames } = await sessionManager.getStackTracePage(
+        sessionId,
+        threadId,
+        startFrame,
+        levels
+      );
+      return { stackFrames, count: stackFrames.length, totalFrames };
+    }
+
+    case 'select_frame': {
+      const sessionId = args.sessionId as string;
+      const frameId = args.frameId as number | undefined;
+      const level = args.level as number | undefined;
+      const direction = args.direction as 'up' | 'down' | undefined;
+      const threadId = args.threadId as number | undefined;
+      return await sessionManager.selectFrame(sessionId, { frameId, level, direction, threadId });
+    }
+
+    case 'get_scopes': {
+      const sessionId = args.sessionId as string;
+      const frameId = args.frameId as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      const scopes = await sessionManager.getScopes(sessionId, frameId, threadId);
+      return { scopes };
     }
 
     case 'get_variables': {
       const sessionId = args.sessionId as string;
       const frameId = args.frameId as number | undefined;
       const scope = args.scope as 'local' | 'global' | 'closure' | undefined;
-      const variables = await sessionManager.getVariables(sessionId, frameId, scope);
+      const depth = args.depth as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      const variables = await sessionManager.getVariables(
+        sessionId,
+        frameId,
+        scope,
+        depth,
+        threadId
+      );
       return { variables };
     }
 
     case 'expand_variable': {
       const sessionId = args.sessionId as string;
       const variablesReference = args.variablesReference as number;
+      const depth = args.depth as number | undefined;
       const variables = await sessionManager.expandVariable(
         sessionId,
-        variablesReference
+        variablesReference,
+        depth
       );
       return { variables };
     }
@@ -742,25 +1776,96 @@ async function handleToolCall(
       const expression = args.expression as string;
       const frameId = args.frameId as number | undefined;
       const context = args.context as 'watch' | 'repl' | 'hover' | undefined;
+      const threadId = args.threadId as number | undefined;
       return sessionManager.evaluateExpression(
         sessionId,
         expression,
         frameId,
-        context
+        context,
+        threadId
       );
     }
 
+    case 'inspect_expression': {
+      const sessionId = args.sessionId as string;
+      const expression = args.expression as string;
+      const depth = args.depth as number | undefined;
+      const frameId = args.frameId as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      return sessionManager.inspectExpression(sessionId, expression, { depth, frameId, threadId });
+    }
+
+    case 'completions': {
+      const sessionId = args.sessionId as string;
+      const text = args.text as string;
+      const column = args.column as number | undefined;
+      const frameId = args.frameId as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      return await sessionManager.getCompletions(sessionId, { text, column, frameId, threadId });
+    }
+
+    case 'set_variable': {
+      const sessionId = args.sessionId as string;
+      const name = args.name as string;
+      const value = args.value as string;
+      const frameId = args.frameId as number | undefined;
+      const variablesReference = args.variablesReference as number | undefined;
+      return sessionManager.setVariable(sessionId, { name, value, frameId, variablesReference });
+    }
+
+    case 'disassemble': {
+      const sessionId = args.sessionId as string;
+      const frameId = args.frameId as number | undefined;
+      const memoryReference = args.memoryReference as string | undefined;
+      const instructionOffset = args.instructionOffset as number | undefined;
+      const instructionCount = args.instructionCount as number | undefined;
+      return sessionManager.disassemble(sessionId, {
+        frameId,
+        memoryReference,
+        instructionOffset,
+        instructionCount
+      });
+    }
+
+    case 'read_memory': {
+      const sessionId = args.sessionId as string;
+      const memoryReference = args.memoryReference as string;
+      const offset = args.offset as number | undefined;
+      const count = args.count as number | undefined;
+      const format = args.format as 'hex' | 'base64' | 'both' | undefined;
+      return sessionManager.readMemory(sessionId, { memoryReference, offset, count, format });
+    }
+
     case 'get_source_context': {
       const sessionId = args.sessionId as string;
       const file = args.file as string | undefined;
       const line = args.line as number | undefined;
+      const frameId = args.frameId as number | undefined;
+      const threadId = args.threadId as number | undefined;
+      const linesContext = args.linesContext as number | undefined;
+      return sessionManager.getSourceContext(sessionId, { file, line, frameId, threadId, linesContext });
+    }
+
+    case 'where': {
+      const sessionId = args.sessionId as string;
+      const threadId = args.threadId as number | undefined;
+      const frames = args.frames as number | undefined;
+      const maxVariables = args.maxVariables as number | undefined;
+      const depth = args.depth as number | undefined;
       const linesContext = args.linesContext as number | undefined;
-      return sessionManager.getSourceContext(sessionId, file, line, linesContext);
+      return sessionManager.getStopContext(sessionId, {
+        threadId,
+        frames,
+        maxVariables,
+        depth,
+        linesContext
+      });
     }
 
     case 'get_threads': {
       const sessionId = args.sessionId as string;
-      const threads = await sessionManager.getThreads(sessionId);
+      const withLocation = args.withLocation as boolean | undefined;
+      const threads = await sessionManager.getThreads(sessionId, { withLocation });
       return { threads };
     }
 
@@ -776,16 +1881,26 @@ export async function startServer(): Promise<void> {
   const server = createServer();
   const transport = new StdioServerTransport();
 
-  // Handle shutdown
-  process.on('SIGINT', async () => {
-    await sessionManager.shutdown();
+  // Handle shutdown: end every session gracefully, then exit. A second
+  // signal while that runs exits at once.
+  let shuttingDown = false;
+  const shutdown = async () => {
+    if (shuttingDown) {
+      process.exit(1);
+    }
+    shuttingDown = true;
+    await sessionManager.shutdown().catch(() => {});
     process.exit(0);
-  });
+  };
 
-  process.on('SIGTERM', async () => {
-    await sessionManager.shutdown();
-    process.exit(0);
-  });
+  process.on('SIGINT', shutdown);
+  process.on('SIGTERM', shutdown);
+  process.on('SIGHUP', shutdown);
+  // The MCP client went away
+  process.stdin.on('close', shutdown);
+
+  // Whatever the exit path, never leave adapters (and the ports they hold) behind
+  process.on('exit', () => sessionManager.killAll());
 
   await server.connect(transport);
 }
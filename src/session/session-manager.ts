@@ -16,25 +16,230 @@ import {
   DebugSessionInfo,
   SessionCreateParams,
   LaunchParams,
+  AttachParams,
   BreakpointInfo,
   SetBreakpointRequest,
+  RemoveBreakpointRequest,
+  BreakpointChangeResult,
+  BreakpointSetResult,
+  FunctionBreakpointInfo,
+  SetFunctionBreakpointRequest,
+  DataBreakpointInfo,
+  SetWatchpointRequest,
+  SetVariableRequest,
+  SetVariableResult,
   StackFrame,
+  FrameSelectionResult,
+  StackTracePage,
   Variable,
   Scope,
   ThreadInfo,
-  EvaluationResult,
+  ExpressionEvaluation,
   SourceContext,
+  SourceContextRequest,
+  StopContext,
+  StopContextRequest,
   SourceLine,
   StopReason,
-  DebugOutput
+  StopInfo,
+  Instruction,
+  MemoryRead,
+  GotoTarget,
+  ExpressionInspection,
+  VariableLimits,
+  CompletionsResult,
+  UnboundBreakpoint,
+  AdapterCapabilities,
+  convertCapabilities,
+  TerminationInfo,
+  TerminationReason,
+  SessionMode,
+  HitBreakpoint,
+  DebugOutput,
+  BufferedOutput
 } from './types.js';
 import { DapClient } from '../dap/dap-client.js';
-import { adapterRegistry, IDebugAdapter } from '../adapters/index.js';
+import { DapTraceOptions, getTraceTargetFromEnv } from '../dap/dap-trace.js';
+import {
+  adapterRegistry,
+  IDebugAdapter,
+  BuildDiagnostic,
+  BuildValidationResult
+} from '../adapters/index.js';
 
 // Max traces to keep in memory per session (prevent OOM)
 const MAX_TRACES_IN_MEMORY = 10000;
 // Max variables per trace (prevent individual traces from being too large)
 const MAX_VARIABLES_PER_TRACE = 100;
+// Max threads (goroutines) whose stacks one call reads, e.g. get_threads locations
+const MAX_THREAD_LOCATIONS = 100;
+// Max depth for variable tree expansion (prevent runaway recursion on cyclic data)
+const MAX_EXPANSION_DEPTH = 5;
+// Default caps on what an inspection returns per variable (override per session with limits)
+const DEFAULT_MAX_STRING_LENGTH = 1000;
+const DEFAULT_MAX_COLLECTION_ENTRIES = 100;
+// Ceilings on per-session limits, so no single call returns an unbounded payload
+const MAX_STRING_LENGTH_CAP = 100000;
+const MAX_COLLECTION_ENTRIES_CAP = 1000;
+// Child names of indexed collections: [0] (Go, Rust) or 0 (Python, JavaScript)
+const INDEXED_CHILD_NAME = /^\[?\d+\]?$/;
+// Max output entries kept per session (ring buffer)
+const MAX_OUTPUT_ENTRIES = 5000;
+// How long a step waits for the program to stop again
+const STEP_TIMEOUT_MS = 5000;
+// Max bytes returned by a single read_memory call
+const MAX_MEMORY_READ_BYTES = 4096;
+// Default idle time before a session is terminated (override with MCP_DEBUGGER_IDLE_TIMEOUT, in seconds)
+const DEFAULT_IDLE_TIMEOUT_MS = 300000;
+// How often idle sessions are checked
+const IDLE_SWEEP_INTERVAL_MS = 10000;
+// How long expired session IDs are remembered for error reporting
+const EXPIRED_SESSION_RETENTION_MS = 3600000;
+// How long after its program ends a session's adapter is released
+const FINISH_GRACE_MS = 1000;
+// Max ended sessions whose exit status and output are kept for get_status/get_output
+const MAX_FINISHED_SESSIONS = 20;
+// Output entries kept per ended session
+const FINISHED_OUTPUT_TAIL = 200;
+// Default cap on concurrent sessions (override with MCP_DEBUGGER_MAX_SESSIONS, 0 = unlimited)
+const DEFAULT_MAX_SESSIONS = 10;
+
+// Default cap on breakpoint stops continue_until passes through
+const DEFAULT_CONTINUE_UNTIL_ITERATIONS = 100;
+// Stop reasons continue_until evaluates at and continues past
+const BREAKPOINT_STOP_REASONS = new Set<string>([
+  'breakpoint',
+  'function breakpoint',
+  'data breakpoint',
+  'instruction breakpoint'
+]);
+// Values that count as false across Go, Python, JavaScript and Rust
+const FALSY_VALUES = new Set(['', 'false', 'False', '0', 'nil', 'None', 'null', 'undefined']);
+
+/**
+ * Whether an evaluated value, as printed by the adapter, counts as true
+ */
+function isTruthy(value: string): boolean {
+  return !FALSY_VALUES.has(value.trim());
+}
+
+/**
+ * Options for the session manager
+ */
+export interface SessionManagerOptions {
+  /** Terminate sessions with no tool activity for this long (ms, 0 disables) */
+  idleTimeoutMs?: number;
+  /** Refuse to create more than this many concurrent sessions (0 disables) */
+  maxSessions?: number;
+}
+
+/**
+ * Read the idle timeout from the environment
+ */
+function getIdleTimeoutFromEnv(): number {
+  const raw = process.env.MCP_DEBUGGER_IDLE_TIMEOUT;
+  const seconds = raw ? Number(raw) : NaN;
+  return Number.isFinite(seconds) && seconds >= 0 ? seconds * 1000 : DEFAULT_IDLE_TIMEOUT_MS;
+}
+
+/**
+ * Read the concurrent session cap from the environment
+ */
+function getMaxSessionsFromEnv(): number {
+  const raw = process.env.MCP_DEBUGGER_MAX_SESSIONS;
+  const max = raw ? Number(raw) : NaN;
+  return Number.isInteger(max) && max >= 0 ? max : DEFAULT_MAX_SESSIONS;
+}
+
+/**
+ * Fill in defaults for a session's variable limits and clamp them to the
+ * server-wide ceilings
+ */
+function resolveVariableLimits(limits: VariableLimits = {}): Required<VariableLimits> {
+  const clamp = (value: number | undefined, fallback: number, cap: number) =>
+    Math.min(Math.max(1, Math.floor(value ?? fallback)), cap);
+  return {
+    maxStringLength: clamp(limits.maxStringLength, DEFAULT_MAX_STRING_LENGTH, MAX_STRING_LENGTH_CAP),
+    maxArrayElements: clamp(limits.maxArrayElements, DEFAULT_MAX_COLLECTION_ENTRIES, MAX_COLLECTION_ENTRIES_CAP),
+    maxMapEntries: clamp(limits.maxMapEntries, DEFAULT_MAX_COLLECTION_ENTRIES, MAX_COLLECTION_ENTRIES_CAP)
+  };
+}
+
+/**
+ * Cut a value down to maxLength characters, saying how much was dropped
+ */
+function truncateValue(value: string, maxLength: number): string {
+  if (value.length <= maxLength) {
+    return value;
+  }
+  return `${value.slice(0, maxLength)}...truncated, ${value.length - maxLength} more chars`;
+}
+
+/**
+ * Decide where a new session records DAP traffic: an explicit trace file,
+ * else MCP_DEBUGGER_TRACE, with trace: true/false overriding the variable
+ */
+function resolveTraceOptions(
+  params: SessionCreateParams,
+  sessionId: string
+): DapTraceOptions | undefined {
+  let target: string | undefined;
+  if (params.traceFile) {
+    target = path.resolve(params.traceFile);
+  } else if (params.trace !== false) {
+    target = getTraceTargetFromEnv() ?? (params.trace ? 'stderr' : undefined);
+  }
+  return target ? { target, label: sessionId } : undefined;
+}
+
+/**
+ * Coarse lifecycle phase reported by get_status
+ */
+export type SessionLifecycle =
+  | 'created'
+  | 'initializing'
+  | 'configured'
+  | 'running'
+  | 'stopped'
+  | 'terminated'
+  | 'error';
+
+const LIFECYCLE_BY_STATE: Record<SessionState, SessionLifecycle> = {
+  [SessionState.CREATED]: 'created',
+  [SessionState.INITIALIZING]: 'initializing',
+  [SessionState.READY]: 'configured',
+  [SessionState.RUNNING]: 'running',
+  [SessionState.PAUSED]: 'stopped',
+  [SessionState.TERMINATED]: 'terminated',
+  [SessionState.ERROR]: 'error'
+};
+
+/**
+ * Check whether a local process is still alive
+ */
+function isProcessAlive(pid: number): boolean {
+  try {
+    process.kill(pid, 0);
+    return true;
+  } catch (error) {
+    // EPERM means the process exists but belongs to another user
+    return (error as NodeJS.ErrnoException).code === 'EPERM';
+  }
+}
+
+/**
+ * Merge launch env vars over the inherited environment so that passing
+ * one variable does not wipe out PATH, HOME, etc. for the debuggee
+ */
+function mergeEnvironment(env?: Record<string, string>): Record<string, string> {
+  const merged: Record<string, string> = {};
+  for (const [key, value] of Object.entries(process.env)) {
+    if (value !== undefined) {
+      merged[key] = value;
+    }
+  }
+  return { ...merged, ...env };
+}
 
 /**
  * Trace point - captured state at a breakpoint hit
@@ -48,6 +253,85 @@ export interface TracePoint {
   variables: Variable[];
 }
 
+/**
+ * Result of starting (launching or attaching) a session
+ */
+export interface SessionStartResult {
+  sessionId: string;
+  success: boolean;
+  state: SessionState;
+  message: string;
+  /** Entry location, when launched with stopOnEntry */
+  stoppedAt?: StackFrame;
+  stopReason?: StopInfo;
+  variables?: Variable[];
+  /** Saved breakpoints that failed to rebind (e.g. the source changed) */
+  unboundBreakpoints?: UnboundBreakpoint[];
+  /** Compile errors that stopped the launch */
+  buildDiagnostics?: BuildDiagnostic[];
+}
+
+/**
+ * Result of a step request
+ */
+export interface StepResult {
+  success: boolean;
+  state: SessionState;
+  message?: string;
+  stoppedAt?: StackFrame;
+  stopReason?: StopInfo;
+  variables?: Variable[];
+}
+
+/**
+ * Result of resuming execution
+ */
+export interface RunResult extends StepResult {
+  /** Whether the program stopped, exited, or is still running */
+  status: 'stopped' | 'running' | 'exited';
+  exitCode?: number;
+  termination?: TerminationInfo;
+  traces?: TracePoint[];
+}
+
+/**
+ * Result of continuing until an expression is true
+ */
+export interface ContinueUntilResult extends RunResult {
+  conditionMet: boolean;
+  /** Breakpoint stops the expression was evaluated at */
+  stops: number;
+  /** Last value of the expression */
+  value?: string;
+}
+
+/**
+ * A session as reported by list_sessions
+ */
+export interface SessionSummary extends DebugSessionInfo {
+  /** Milliseconds since the last tool call on the session */
+  idleMs: number;
+}
+
+/**
+ * Result of jumping to a line with goto
+ */
+export interface GotoResult extends StepResult {
+  target?: GotoTarget;
+  /** Whether the program counter is now on the target line */
+  moved?: boolean;
+}
+
+/**
+ * What is kept of a session once its program ended and its adapter was
+ * released: the exit status and output tail for get_status/get_output, and
+ * the launch settings and breakpoints so restart can run it again
+ */
+interface FinishedSession {
+  session: SessionData;
+  finishedAt: number;
+}
+
 /**
  * Internal session data
  */
@@ -57,17 +341,47 @@ interface SessionData {
   client: DapClient;
   executablePath: string;
   breakpoints: Map<string, BreakpointInfo[]>; // file -> breakpoints
+  functionBreakpoints: FunctionBreakpointInfo[];
+  // Data IDs are only valid for the current run, so these are not replayed
+  dataBreakpoints: DataBreakpointInfo[];
+  exceptionFilters: string[];
   currentThreadId: number;
   currentFrameId: number;
+  // Caps applied to every variable and evaluation result returned
+  limits: Required<VariableLimits>;
+  // Targets from the last goto_targets call, so goto can check where a targetId leads
+  gotoTargets?: Map<number, GotoTarget>;
+  // Frame chosen with select_frame; unset means the top frame of the stopped thread
+  frameSelection?: { threadId: number; level: number };
   // Cached context from last stop (for returning with step/continue)
   lastStopContext?: {
     stackFrame: StackFrame;
     variables: Variable[];
   };
+  // Reason for the most recent stop
+  lastStop?: StopInfo;
+  // Launch parameters, kept so the program can be restarted
+  launchParams?: LaunchParams;
+  // Set when a tool asked to stop the debuggee
+  terminationRequested?: boolean;
   // Collected traces when using collectHits mode
   collectedTraces: TracePoint[];
   // Map of "file:line" -> dumpFile for tracepoint breakpoints
   dumpBreakpoints: Map<string, string>;
+  // Tail of the queue of setBreakpoints requests, which must not overlap
+  breakpointUpdates: Promise<unknown>;
+  // Where DAP traffic is recorded, if tracing is on
+  trace?: DapTraceOptions;
+  // Debuggee output ring buffer
+  outputBuffer: BufferedOutput[];
+  // Sequence number of the last buffered output
+  outputSeq: number;
+  // Sequence number of the last output returned by an unfiltered getOutput
+  outputReadSeq: number;
+  // Per-category read cursors, advanced by getOutput with a category filter
+  outputCategoryReadSeq: Map<string, number>;
+  // Time of the last tool interaction (ms since epoch)
+  lastActivity: number;
 }
 
 /**
@@ -93,16 +407,194 @@ export interface SessionManagerEvents {
 
 export class SessionManager extends EventEmitter {
   private sessions: Map<string, SessionData> = new Map();
-
-  constructor() {
+  // Session ID -> time it was expired for inactivity
+  private expiredSessions: Map<string, number> = new Map();
+  // Sessions whose program ended, oldest first
+  private finishedSessions: Map<string, FinishedSession> = new Map();
+  private idleTimeoutMs: number;
+  private idleSweepTimer: ReturnType<typeof setInterval> | null = null;
+  private maxSessions: number;
+  // Sessions still starting their adapter, counted against maxSessions
+  private pendingSessions: number = 0;
+
+  constructor(options: SessionManagerOptions = {}) {
     super();
+    this.idleTimeoutMs = options.idleTimeoutMs ?? getIdleTimeoutFromEnv();
+    this.maxSessions = options.maxSessions ?? getMaxSessionsFromEnv();
+
+    if (this.idleTimeoutMs > 0) {
+      this.idleSweepTimer = setInterval(() => {
+        this.expireIdleSessions().catch(() => {});
+      }, Math.min(IDLE_SWEEP_INTERVAL_MS, this.idleTimeoutMs));
+      // Don't keep the process alive just for the sweep
+      this.idleSweepTimer.unref();
+    }
+  }
+
+  /**
+   * Terminate sessions that have not been used within the idle timeout
+   */
+  private async expireIdleSessions(): Promise<void> {
+    const now = Date.now();
+
+    for (const [sessionId, session] of this.sessions) {
+      if (now - session.lastActivity < this.idleTimeoutMs) {
+        continue;
+      }
+      await this.terminateSession(sessionId).catch(() => {});
+      this.expiredSessions.set(sessionId, now);
+    }
+
+    for (const [sessionId, expiredAt] of this.expiredSessions) {
+      if (now - expiredAt > EXPIRED_SESSION_RETENTION_MS) {
+        this.expiredSessions.delete(sessionId);
+      }
+    }
+    this.pruneFinishedSessions(now);
+  }
+
+  /**
+   * Drop ended-session records past the retention time or the count limit
+   */
+  private pruneFinishedSessions(now: number): void {
+    for (const [sessionId, finished] of this.finishedSessions) {
+      if (
+        now - finished.finishedAt > EXPIRED_SESSION_RETENTION_MS ||
+        this.finishedSessions.size > MAX_FINISHED_SESSIONS
+      ) {
+        this.finishedSessions.delete(sessionId);
+      }
+    }
+  }
+
+  /**
+   * Once the program has ended, release the session's adapter and keep only
+   * a small record of it. The short delay lets late events such as 'exited'
+   * (sent after 'terminated' by some adapters) still reach the session.
+   */
+  private scheduleRelease(sessionId: string): void {
+    const session = this.sessions.get(sessionId);
+    if (!session) {
+      return;
+    }
+    const client = session.client;
+
+    setTimeout(() => {
+      // A restart in the meantime brought the session back to life
+      const state = session.info.state;
+      if (
+        this.sessions.get(sessionId) !== session ||
+        session.client !== client ||
+        (state !== SessionState.TERMINATED && state !== SessionState.ERROR)
+      ) {
+        return;
+      }
+
+      this.sessions.delete(sessionId);
+      client.removeAllListeners();
+      client.on('error', () => {});
+      client.disconnect(session.info.mode !== 'attach').catch(() => {});
+
+      session.outputBuffer = session.outputBuffer.slice(-FINISHED_OUTPUT_TAIL);
+      session.collectedTraces = [];
+      session.lastStopContext = undefined;
+      this.finishedSessions.set(sessionId, { session, finishedAt: Date.now() });
+      this.pruneFinishedSessions(Date.now());
+    }, FINISH_GRACE_MS).unref();
+  }
+
+  /**
+   * Mark a session failed and shut its adapter down, so a failed start or
+   * attach does not leave an adapter process behind
+   */
+  private failSession(sessionId: string, session: SessionData, error: unknown): void {
+    session.info.error = error instanceof Error ? error.message : String(error);
+    this.updateState(sessionId, SessionState.ERROR);
+    // An attached program keeps running; a launched one goes with the adapter
+    session.client.disconnect(session.info.mode !== 'attach').catch(() => {});
+    this.scheduleRelease(sessionId);
+  }
+
+  /**
+   * Describe how an ended session's program finished, for lookup errors
+   */
+  private describeFinished(sessionId: string, session: SessionData): string {
+    if (session.info.state === SessionState.ERROR) {
+      return `Session ${sessionId} failed: ${session.info.error ?? 'debug adapter error'}`;
+    }
+    const termination = session.info.termination;
+    const code = session.info.exitCode ?? termination?.exitCode;
+    const how = termination?.reason === 'signal'
+      ? `was killed by signal ${termination.signal}`
+      : `exited with code ${code ?? 'unknown'}`;
+    return `Program in session ${sessionId} ${how}`;
   }
 
   /**
-   * Create a new debug session
+   * Create a new debug session, unless the concurrent session cap is reached
    */
   async createSession(params: SessionCreateParams): Promise<DebugSessionInfo> {
-    const { language, name, executablePath } = params;
+    // Each session runs an adapter and a debuggee; refuse before spawning more
+    if (this.atSessionLimit()) {
+      throw new Error(this.describeSessionLimit());
+    }
+
+    this.pendingSessions++;
+    try {
+      return await this.openSession(params);
+    } finally {
+      this.pendingSessions--;
+    }
+  }
+
+  /**
+   * Whether another live session would exceed maxSessions. Sessions whose
+   * program ended or failed do not count: their adapter is shut down as they
+   * end (see scheduleRelease and failSession).
+   */
+  private atSessionLimit(): boolean {
+    if (this.maxSessions <= 0) {
+      return false;
+    }
+    let live = this.pendingSessions;
+    for (const session of this.sessions.values()) {
+      if (session.info.state !== SessionState.TERMINATED && session.info.state !== SessionState.ERROR) {
+        live++;
+      }
+    }
+    return live >= this.maxSessions;
+  }
+
+  private describeSessionLimit(): string {
+    return (
+      `Session limit reached: ${this.maxSessions} debug sessions are already open (MCP_DEBUGGER_MAX_SESSIONS). ` +
+      'Terminate one you no longer need with terminate_session (see list_sessions), or all of them with terminate_all.'
+    );
+  }
+
+  /**
+   * Start the adapter for a new session and register it
+   */
+  private async openSession(params: SessionCreateParams): Promise<DebugSessionInfo> {
+    const { name, executablePath, scriptPath } = params;
+
+    if (params.language && !adapterRegistry.resolveLanguage(params.language)) {
+      throw new Error(`Language '${params.language}' is not supported`);
+    }
+
+    // Fall back to detecting the language from the entry point's extension
+    const language = params.language
+      ? adapterRegistry.resolveLanguage(params.language)
+      : scriptPath
+        ? adapterRegistry.detectLanguage(scriptPath)
+        : null;
+    if (!language) {
+      throw new Error(
+        scriptPath
+          ? `Could not detect language from '${scriptPath}'. Specify a language explicitly.`
+          : 'Specify a language or a scriptPath to detect it from'
+      );
+    }
 
     // Validate language is supported
     if (!adapterRegistry.isSupported(language)) {
@@ -121,20 +613,12 @@ export class SessionManager extends EventEmitter {
       await adapter.install();
     }
 
-    // Get adapter command
-    const adapterCommand = await adapter.getAdapterCommand();
-
-    // Create DAP client
-    const client = new DapClient({
-      command: adapterCommand.command,
-      args: adapterCommand.args,
-      env: adapterCommand.env,
-      cwd: adapterCommand.cwd,
-      mode: adapterCommand.mode
-    });
-
     // Generate session ID
     const sessionId = randomUUID();
+    const trace = resolveTraceOptions(params, sessionId);
+
+    // Create DAP client
+    const client = await this.createClient(adapter, trace);
     const sessionName = name ?? `${language}-debug-${sessionId.substring(0, 8)}`;
 
     // Create session info
@@ -143,6 +627,7 @@ export class SessionManager extends EventEmitter {
       name: sessionName,
       language,
       state: SessionState.CREATED,
+      scriptPath,
       createdAt: new Date()
     };
 
@@ -153,10 +638,21 @@ export class SessionManager extends EventEmitter {
       client,
       executablePath: resolvedPath,
       breakpoints: new Map(),
+      functionBreakpoints: [],
+      dataBreakpoints: [],
+      exceptionFilters: [],
       currentThreadId: 1,
       currentFrameId: 0,
       collectedTraces: [],
-      dumpBreakpoints: new Map()
+      dumpBreakpoints: new Map(),
+      breakpointUpdates: Promise.resolve(),
+      trace,
+      limits: resolveVariableLimits(params.limits),
+      outputBuffer: [],
+      outputSeq: 0,
+      outputReadSeq: 0,
+      outputCategoryReadSeq: new Map(),
+      lastActivity: Date.now()
     };
 
     this.sessions.set(sessionId, sessionData);
@@ -168,6 +664,43 @@ export class SessionManager extends EventEmitter {
     return info;
   }
 
+  /**
+   * Map adapter breakpoint IDs from a 'stopped' event to the breakpoints we set
+   */
+  private resolveHitBreakpoints(session: SessionData, ids: number[]): HitBreakpoint[] {
+    return ids.map((id) => {
+      for (const breakpoints of session.breakpoints.values()) {
+        const bp = breakpoints.find((b) => b.id === id);
+        if (bp) {
+          return { id, kind: 'line', location: `${bp.file}:${bp.line}` };
+        }
+      }
+
+      const fbp = session.functionBreakpoints.find((b) => b.id === id);
+      if (fbp) {
+        return { id, kind: 'function', location: fbp.name };
+      }
+
+      const dbp = session.dataBreakpoints.find((b) => b.id === id);
+      return { id, kind: 'data', location: dbp?.name ?? 'unknown' };
+    });
+  }
+
+  /**
+   * Create a DAP client for an adapter's launch command
+   */
+  private async createClient(adapter: IDebugAdapter, trace?: DapTraceOptions): Promise<DapClient> {
+    const adapterCommand = await adapter.getAdapterCommand();
+    return new DapClient({
+      command: adapterCommand.command,
+      args: adapterCommand.args,
+      env: adapterCommand.env,
+      cwd: adapterCommand.cwd,
+      mode: adapterCommand.mode,
+      trace
+    });
+  }
+
   /**
    * Set up event handlers for a DAP client
    */
@@ -178,10 +711,57 @@ export class SessionManager extends EventEmitter {
 
     client.on('stopped', async (event: DebugProtocol.StoppedEvent) => {
       const session = this.sessions.get(sessionId);
+
+      // Some adapters stop on entry regardless; run on unless it was asked for
+      if (
+        session &&
+        event.body.reason === 'entry' &&
+        session.info.mode === 'launch' &&
+        !session.launchParams?.stopOnEntry
+      ) {
+        setImmediate(async () => {
+          try {
+            await client.continue(event.body.threadId ?? session.currentThreadId);
+          } catch {
+            // Program may have terminated
+          }
+        });
+        return;
+      }
+
       if (session) {
         session.currentThreadId = event.body.threadId ?? 1;
         session.info.stoppedReason = event.body.reason as StopReason;
         session.info.stoppedThreadId = session.currentThreadId;
+        const stop: StopInfo = {
+          reason: event.body.reason as StopReason,
+          threadId: session.currentThreadId,
+          description: event.body.description,
+          text: event.body.text,
+          allThreadsStopped: event.body.allThreadsStopped,
+          hitBreakpoints: event.body.hitBreakpointIds
+            ? this.resolveHitBreakpoints(session, event.body.hitBreakpointIds)
+            : undefined
+        };
+        session.lastStop = stop;
+
+        if (
+          event.body.reason === 'exception' &&
+          session.client.getCapabilities()?.supportsExceptionInfoRequest
+        ) {
+          try {
+            const info = await client.exceptionInfo(session.currentThreadId);
+            stop.exception = {
+              exceptionId: info.exceptionId,
+              description: info.description,
+              breakMode: info.breakMode,
+              typeName: info.details?.typeName ?? info.details?.fullTypeName,
+              stackTrace: info.details?.stackTrace
+            };
+          } catch {
+            // Fall back to the event's description/text
+          }
+        }
 
         // Auto-fetch stack trace and variables (like VSCode does on stop)
         // This prevents "Invalid frame reference" errors and caches context
@@ -191,6 +771,8 @@ export class SessionManager extends EventEmitter {
           const frames = await client.stackTrace(session.currentThreadId);
           if (frames.length > 0) {
             session.currentFrameId = frames[0].id;
+            session.frameSelection = undefined;
+            session.gotoTargets = undefined;
             currentFile = frames[0].file;
             currentLine = frames[0].line;
 
@@ -199,7 +781,10 @@ export class SessionManager extends EventEmitter {
             const localScope = scopes.find(s => s.name.toLowerCase().includes('local'));
             let variables: Variable[] = [];
             if (localScope) {
-              variables = await client.variables(localScope.variablesReference);
+              variables = this.limitVariables(
+                session,
+                await client.variables(localScope.variablesReference)
+              );
             }
 
             // Cache context for returning with step/continue responses
@@ -290,18 +875,48 @@ export class SessionManager extends EventEmitter {
       const session = this.sessions.get(sessionId);
       if (session) {
         session.info.exitCode = event.body.exitCode;
+        // Some adapters report the exit code after 'terminated'
+        if (session.info.termination && session.info.termination.exitCode === undefined) {
+          session.info.termination = this.getTermination(session, 'exited');
+        }
       }
     });
 
+    // Ended sessions give up their adapter; their exit status and output
+    // stay queryable (see scheduleRelease)
     client.on('terminated', () => {
+      const session = this.sessions.get(sessionId);
+      if (session && !session.info.termination) {
+        session.info.termination = this.getTermination(session, 'exited');
+      }
       this.updateState(sessionId, SessionState.TERMINATED);
       this.emit('sessionTerminated', sessionId);
-      // Auto-cleanup terminated sessions after a short delay
-      setTimeout(() => {
-        if (this.sessions.get(sessionId)?.info.state === SessionState.TERMINATED) {
-          this.sessions.delete(sessionId);
+      this.scheduleRelease(sessionId);
+    });
+
+    client.on('breakpoint', (event: DebugProtocol.BreakpointEvent) => {
+      // Adapters report late binding (e.g. once a module loads) or relocation
+      const session = this.sessions.get(sessionId);
+      const changed = event.body.breakpoint;
+      if (!session || changed.id === undefined || event.body.reason !== 'changed') {
+        return;
+      }
+
+      for (const breakpoints of session.breakpoints.values()) {
+        const bp = breakpoints.find((b) => b.id === changed.id);
+        if (bp) {
+          const oldKey = `${bp.file}:${bp.line}`;
+          bp.verified = changed.verified;
+          bp.line = changed.line ?? bp.line;
+          bp.message = changed.message;
+
+          if (session.dumpBreakpoints.has(oldKey) && oldKey !== `${bp.file}:${bp.line}`) {
+            session.dumpBreakpoints.set(`${bp.file}:${bp.line}`, session.dumpBreakpoints.get(oldKey)!);
+            session.dumpBreakpoints.delete(oldKey);
+          }
+          return;
         }
-      }, 5000);
+      }
     });
 
     client.on('output', (event: DebugProtocol.OutputEvent) => {
@@ -312,32 +927,74 @@ export class SessionManager extends EventEmitter {
         line: event.body.line,
         column: event.body.column
       };
+
+      // Buffer output so it survives between tool calls
+      const session = this.sessions.get(sessionId);
+      if (session && output.category !== 'telemetry') {
+        if (session.outputBuffer.length >= MAX_OUTPUT_ENTRIES) {
+          session.outputBuffer.shift(); // Remove oldest
+        }
+        session.outputBuffer.push({
+          ...output,
+          category: output.category ?? 'console',
+          seq: ++session.outputSeq,
+          timestamp: Date.now()
+        });
+      }
+
       this.emit('output', sessionId, output);
     });
 
-    client.on('adapterExit', (code: number | null) => {
-      if (this.sessions.has(sessionId)) {
-        this.updateState(sessionId, SessionState.TERMINATED);
+    client.on('adapterExit', (code: number | null, reason?: string) => {
+      const session = this.sessions.get(sessionId);
+      if (session) {
+        if (!session.info.termination) {
+          session.info.termination = this.getTermination(session, 'adapter-exit');
+        }
+        // An adapter (or remote server) that goes away before the program
+        // ends is a failure, not an exit
+        if (
+          reason &&
+          !session.terminationRequested &&
+          session.info.state !== SessionState.TERMINATED
+        ) {
+          session.info.error = reason;
+          this.updateState(sessionId, SessionState.ERROR);
+        } else {
+          this.updateState(sessionId, SessionState.TERMINATED);
+        }
         this.emit('sessionTerminated', sessionId);
-        // Auto-cleanup after adapter exit
-        setTimeout(() => {
-          if (this.sessions.get(sessionId)?.info.state === SessionState.TERMINATED) {
-            this.sessions.delete(sessionId);
-          }
-        }, 5000);
+        this.scheduleRelease(sessionId);
       }
     });
 
     client.on('error', (error: Error) => {
-      this.updateState(sessionId, SessionState.ERROR);
       const session = this.sessions.get(sessionId);
       if (session) {
-        session.info.error = error.message;
+        this.failSession(sessionId, session, error);
       }
       this.emit('error', sessionId, error);
     });
   }
 
+  /**
+   * Classify how the debuggee ended from its exit code
+   */
+  private getTermination(session: SessionData, fallback: TerminationReason): TerminationInfo {
+    const exitCode = session.info.exitCode;
+    if (session.terminationRequested) {
+      return { reason: 'debugger', exitCode };
+    }
+    if (exitCode === undefined) {
+      return { reason: fallback };
+    }
+    // Shells and Delve report death by signal N as exit code 128 + N
+    if (process.platform !== 'win32' && exitCode > 128 && exitCode < 160) {
+      return { reason: 'signal', exitCode, signal: exitCode - 128 };
+    }
+    return { reason: 'exited', exitCode };
+  }
+
   /**
    * Update session state
    */
@@ -346,6 +1003,11 @@ export class SessionManager extends EventEmitter {
     if (session) {
       const previousState = session.info.state;
       session.info.state = newState;
+      // A stop context describes one stop; never report it once execution resumes
+      if (newState === SessionState.RUNNING) {
+        session.lastStop = undefined;
+        session.lastStopContext = undefined;
+      }
       this.emit('sessionStateChanged', sessionId, newState, previousState);
     }
   }
@@ -356,62 +1018,110 @@ export class SessionManager extends EventEmitter {
   async startDebugging(
     sessionId: string,
     params: LaunchParams
-  ): Promise<{ success: boolean; state: SessionState; message: string }> {
+  ): Promise<SessionStartResult> {
     const session = this.getSession(sessionId);
 
-    // Update session info
-    session.info.scriptPath = params.scriptPath;
-    session.info.workingDirectory = params.cwd;
-
-    try {
-      // Start the DAP client
-      this.updateState(sessionId, SessionState.INITIALIZING);
-      await session.client.start();
+    // A session debugs one program; starting again would clobber it
+    if (session.info.state !== SessionState.CREATED) {
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: 'Session already started. Create a new session to debug another program.'
+      };
+    }
 
-      // Initialize the adapter
-      await session.client.initialize();
+    const remote = params.port !== undefined;
+    if (remote && !session.adapter.attachesToDapServer) {
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: `${session.adapter.name} does not support connecting to a remote debug server`
+      };
+    }
 
-      // Build launch configuration
-      const launchConfig = session.adapter.buildLaunchConfig(
-        params,
-        session.executablePath
-      );
+    if ((params.test || params.testFilter !== undefined) && !session.adapter.supportsTests) {
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: `${session.adapter.name} does not support debugging tests; launch the test runner as the program instead`
+      };
+    }
 
-      // Set up promise to wait for initialized event BEFORE launching
-      // Note: Some adapters (like Delve) send initialized AFTER launch request
-      const initializedPromise = this.waitForInitialized(session.client);
+    let cwd: string | undefined;
+    if (remote) {
+      // Paths belong to the remote machine, so they cannot be checked here
+      cwd = params.cwd;
+    } else if (params.cwd) {
+      cwd = path.resolve(params.cwd);
+      const stat = await fs.stat(cwd).catch(() => undefined);
+      if (!stat?.isDirectory()) {
+        return {
+          sessionId,
+          success: false,
+          state: session.info.state,
+          message: `Working directory does not exist: ${cwd}`
+        };
+      }
+    }
 
-      // Launch the program (async - response timing varies by adapter)
-      session.client.launchAsync(launchConfig);
+    // Report compile errors directly instead of a noisy adapter launch failure
+    const build = await this.buildLaunchTarget(session, { ...params, cwd });
+    if (build && !build.success) {
+      return this.describeBuildFailure(sessionId, session, build);
+    }
 
-      // Wait for initialized event (may come after launch for some adapters)
-      await initializedPromise;
+    // Keeps the source path, so restart rebuilds instead of reusing the binary
+    const launchParams: LaunchParams = {
+      ...params,
+      cwd,
+      // The local environment means nothing to a remote debuggee
+      env: remote ? params.env : mergeEnvironment(params.env)
+    };
 
-      // Now we're ready to set breakpoints
-      this.updateState(sessionId, SessionState.READY);
+    // Update session info
+    session.info.scriptPath = params.scriptPath;
+    session.info.workingDirectory = cwd;
+    session.info.mode = 'launch';
+    session.launchParams = launchParams;
 
-      // Set breakpoints (after initialized event)
-      for (const [file, breakpoints] of session.breakpoints) {
-        await this.setBreakpointsInternal(session, file, breakpoints);
+    try {
+      if (remote) {
+        this.connectRemote(sessionId, session, params.host, params.port!);
       }
 
-      // Signal configuration done
-      await session.client.configurationDone();
-
-      // Wait for launch response (with timeout - don't fail if it takes time)
-      await session.client.waitForLaunch(2000);
-
-      this.updateState(sessionId, SessionState.RUNNING);
+      const unbound = await this.launch(sessionId, session, this.withArtifact(launchParams, build));
+      const unboundBreakpoints = unbound.length > 0 ? unbound : undefined;
+
+      if (launchParams.stopOnEntry) {
+        await this.waitForPause(sessionId);
+        if (session.info.state === SessionState.PAUSED) {
+          return {
+            sessionId,
+            success: true,
+            state: session.info.state,
+            message: `Debugging started, paused on entry${this.describeUnbound(unbound)}`,
+            stoppedAt: session.lastStopContext?.stackFrame,
+            stopReason: session.lastStop,
+            variables: session.lastStopContext?.variables,
+            unboundBreakpoints
+          };
+        }
+      }
 
       return {
+        sessionId,
         success: true,
         state: session.info.state,
-        message: 'Debugging started successfully'
+        message: `Debugging started successfully${this.describeUnbound(unbound)}`,
+        unboundBreakpoints
       };
     } catch (error) {
-      this.updateState(sessionId, SessionState.ERROR);
-      session.info.error = error instanceof Error ? error.message : String(error);
+      this.failSession(sessionId, session, error);
       return {
+        sessionId,
         success: false,
         state: session.info.state,
         message: `Failed to start debugging: ${session.info.error}`
@@ -420,164 +1130,1294 @@ export class SessionManager extends EventEmitter {
   }
 
   /**
-   * Wait for the initialized event from the debug adapter
+   * Start the adapter and launch the program
+   */
+  private async launch(
+    sessionId: string,
+    session: SessionData,
+    launchParams: LaunchParams
+  ): Promise<UnboundBreakpoint[]> {
+    // Start the DAP client
+    this.updateState(sessionId, SessionState.INITIALIZING);
+    await session.client.start();
+
+    // Initialize the adapter
+    await session.client.initialize();
+
+    // Build launch configuration
+    const launchConfig = session.adapter.buildLaunchConfig(
+      launchParams,
+      session.executablePath
+    );
+
+    // Launch the program (async - response timing varies by adapter)
+    return this.completeHandshake(sessionId, session, () =>
+      session.client.launchAsync(launchConfig)
+    );
+  }
+
+  /**
+   * Compile the launch target without starting a debug session
+   */
+  async validateBuild(
+    sessionId: string,
+    params: Pick<LaunchParams, 'scriptPath' | 'cwd' | 'env' | 'test'>
+  ): Promise<BuildValidationResult> {
+    const session = this.getSession(sessionId);
+
+    if (!session.adapter.validateBuild) {
+      return {
+        success: true,
+        skipped: true,
+        diagnostics: [],
+        message: `${session.adapter.name} has no build step to validate`
+      };
+    }
+
+    return session.adapter.validateBuild({
+      ...params,
+      cwd: params.cwd ? path.resolve(params.cwd) : undefined
+    });
+  }
+
+  /**
+   * Compile a local launch target when the adapter has a build step and
+   * validation is not turned off; undefined when nothing was built
+   */
+  private async buildLaunchTarget(
+    session: SessionData,
+    params: LaunchParams
+  ): Promise<BuildValidationResult | undefined> {
+    if (params.port !== undefined || params.validateBuild === false || !session.adapter.validateBuild) {
+      return undefined;
+    }
+    return session.adapter.validateBuild(params);
+  }
+
+  /**
+   * Launch parameters pointing at what the build produced, e.g. the binary
+   * cargo built from a .rs file or Cargo.toml
+   */
+  private withArtifact(params: LaunchParams, build: BuildValidationResult | undefined): LaunchParams {
+    return build?.artifact ? { ...params, scriptPath: build.artifact } : params;
+  }
+
+  private describeBuildFailure(
+    sessionId: string,
+    session: SessionData,
+    build: BuildValidationResult
+  ): SessionStartResult {
+    return {
+      sessionId,
+      success: false,
+      state: session.info.state,
+      message: build.output ? `${build.message}:\n${build.output}` : build.message,
+      buildDiagnostics: build.diagnostics.length > 0 ? build.diagnostics : undefined
+    };
+  }
+
+  /**
+   * Re-run the program from the start, keeping the session ID, breakpoints
+   * and launch arguments. Uses DAP restart when supported, else relaunches.
+   */
+  async restartSession(sessionId: string): Promise<SessionStartResult> {
+    // A session whose program ended comes back with a fresh adapter below
+    const finished = this.finishedSessions.get(sessionId);
+    if (finished) {
+      if (this.atSessionLimit()) {
+        return {
+          sessionId,
+          success: false,
+          state: finished.session.info.state,
+          message: this.describeSessionLimit()
+        };
+      }
+      this.finishedSessions.delete(sessionId);
+      this.sessions.set(sessionId, finished.session);
+    }
+    const session = this.getSession(sessionId);
+    const launchParams = session.launchParams;
+    // Refusing leaves a revived session ended, so release it again
+    const refuse = (message: string): SessionStartResult => {
+      if (finished) {
+        this.scheduleRelease(sessionId);
+      }
+      return { sessionId, success: false, state: session.info.state, message };
+    };
+
+    if (session.info.mode !== 'launch' || !launchParams) {
+      return refuse('Only launched sessions can be restarted. Use start_debugging first.');
+    }
+
+    const active =
+      session.info.state === SessionState.READY ||
+      session.info.state === SessionState.RUNNING ||
+      session.info.state === SessionState.PAUSED;
+    const inPlace = active && session.client.getCapabilities()?.supportsRestartRequest === true;
+
+    // A debug server such as `dlv dap --listen` runs one program and exits
+    // once disconnected, so without DAP restart there is nothing to relaunch on
+    const remote = launchParams.port !== undefined;
+    if (remote && !inPlace) {
+      return refuse(
+        'The remote debug server does not support restarting the program. ' +
+        'Start the program on the server again and connect a new session with start_debugging.'
+      );
+    }
+
+    // Rebuild so edits made since the last run are picked up
+    const build = await this.buildLaunchTarget(session, launchParams);
+    if (build && !build.success) {
+      if (finished) {
+        this.scheduleRelease(sessionId);
+      }
+      return this.describeBuildFailure(sessionId, session, build);
+    }
+    const target = this.withArtifact(launchParams, build);
+
+    this.resetRunState(session);
+
+    if (inPlace) {
+      try {
+        const launchConfig = session.adapter.buildLaunchConfig(
+          target,
+          session.executablePath
+        );
+        await session.client.restart(launchConfig);
+        this.updateState(sessionId, SessionState.RUNNING);
+        return {
+          sessionId,
+          success: true,
+          state: session.info.state,
+          message: 'Program restarted'
+        };
+      } catch (error) {
+        if (remote) {
+          return {
+            sessionId,
+            success: false,
+            state: session.info.state,
+            message: `Failed to restart: ${error instanceof Error ? error.message : String(error)}`
+          };
+        }
+        // Fall back to a full relaunch
+      }
+    }
+
+    try {
+      // Swap in a fresh adapter before killing the old one so its
+      // termination events do not affect the session
+      const oldClient = session.client;
+      this.replaceClient(sessionId, session, await this.createClient(session.adapter, session.trace));
+      oldClient.on('error', () => {});
+      await oldClient.disconnect(true);
+
+      const unbound = await this.launch(sessionId, session, target);
+
+      return {
+        sessionId,
+        success: true,
+        state: session.info.state,
+        message: `Program relaunched${this.describeUnbound(unbound)}`,
+        unboundBreakpoints: unbound.length > 0 ? unbound : undefined
+      };
+    } catch (error) {
+      this.failSession(sessionId, session, error);
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: `Failed to restart: ${session.info.error}`
+      };
+    }
+  }
+
+  /**
+   * Clear state that only applies to the previous run of the program
+   */
+  private resetRunState(session: SessionData): void {
+    session.info.exitCode = undefined;
+    session.info.termination = undefined;
+    session.terminationRequested = false;
+    session.info.error = undefined;
+    session.info.stoppedReason = undefined;
+    session.info.stoppedThreadId = undefined;
+    session.currentThreadId = 1;
+    session.currentFrameId = 0;
+    session.frameSelection = undefined;
+    session.gotoTargets = undefined;
+    session.lastStop = undefined;
+    session.lastStopContext = undefined;
+    // Data IDs do not survive a restart
+    session.dataBreakpoints = [];
+    for (const breakpoints of session.breakpoints.values()) {
+      for (const bp of breakpoints) {
+        bp.dumpCount = 0;
+      }
+    }
+  }
+
+  /**
+   * Attach to an already-running program
+   */
+  async attachDebugging(
+    sessionId: string,
+    params: AttachParams
+  ): Promise<SessionStartResult> {
+    const session = this.getSession(sessionId);
+
+    if (session.info.state !== SessionState.CREATED) {
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: 'Session already started. Create a new session to debug another program.'
+      };
+    }
+
+    if (params.processId === undefined && params.port === undefined) {
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: 'Attach requires either a processId or a port'
+      };
+    }
+
+    // Update session info
+    session.info.mode = 'attach';
+    session.info.processId = params.processId;
+    session.info.workingDirectory = params.cwd;
+
+    try {
+      // Fail fast on a stale PID instead of waiting for the adapter to time out
+      if (params.processId !== undefined && !isProcessAlive(params.processId)) {
+        throw new Error(`Process ${params.processId} is not running (it may have exited)`);
+      }
+
+      // A host/port that already speaks DAP is dialed directly instead of
+      // spawning a local adapter
+      if (params.processId === undefined && session.adapter.attachesToDapServer) {
+        this.connectRemote(sessionId, session, params.host, params.port!);
+      }
+
+      // Start the DAP client
+      this.updateState(sessionId, SessionState.INITIALIZING);
+      await session.client.start();
+
+      // Initialize the adapter
+      await session.client.initialize();
+
+      // Build attach configuration
+      const attachConfig = session.adapter.buildAttachConfig(params);
+
+      const unbound = await this.completeHandshake(sessionId, session, () =>
+        session.client.attachAsync(attachConfig)
+      );
+
+      const target = params.processId !== undefined
+        ? `process ${params.processId}`
+        : `debug server at ${params.host ?? '127.0.0.1'}:${params.port}`;
+      return {
+        sessionId,
+        success: true,
+        state: session.info.state,
+        message: `Attached to ${target}${this.describeUnbound(unbound)}`,
+        unboundBreakpoints: unbound.length > 0 ? unbound : undefined
+      };
+    } catch (error) {
+      this.failSession(sessionId, session, error);
+      return {
+        sessionId,
+        success: false,
+        state: session.info.state,
+        message: `Failed to attach: ${session.info.error}`
+      };
+    }
+  }
+
+  /**
+   * Run the shared launch/attach handshake: send the request, wait for
+   * initialized, apply breakpoints and signal configurationDone
+   */
+  private async completeHandshake(
+    sessionId: string,
+    session: SessionData,
+    sendRequest: () => void
+  ): Promise<UnboundBreakpoint[]> {
+    // Set up promise to wait for initialized event BEFORE sending the request
+    // Note: Some adapters (like Delve) send initialized AFTER launch/attach
+    const initializedPromise = this.waitForInitialized(session.client);
+
+    sendRequest();
+
+    // Wait for initialized event (may come after launch for some adapters)
+    await initializedPromise;
+
+    // Now we're ready to set breakpoints
+    this.updateState(sessionId, SessionState.READY);
+
+    // Set breakpoints (after initialized event)
+    const unbound = await this.replayBreakpoints(session);
+    if (session.exceptionFilters.length > 0) {
+      // Filters were chosen before the adapter reported which ones it has
+      const available = this.getExceptionFilters(session).map((f) => f.filter);
+      session.exceptionFilters = session.exceptionFilters.filter((f) => available.includes(f));
+      await session.client.setExceptionBreakpoints(session.exceptionFilters);
+    }
+
+    // Signal configuration done
+    await session.client.configurationDone();
+
+    // Wait for launch/attach response (with timeout - don't fail if it takes time)
+    await session.client.waitForLaunch(2000);
+
+    // An entry or breakpoint stop may already have arrived; don't mask it
+    if (session.info.state === SessionState.READY) {
+      this.updateState(sessionId, SessionState.RUNNING);
+    }
+
+    return unbound;
+  }
+
+  /**
+   * Send every saved breakpoint to a freshly started adapter. Breakpoints
+   * that fail to bind stay saved and are returned so they can be reported.
+   */
+  private async replayBreakpoints(session: SessionData): Promise<UnboundBreakpoint[]> {
+    const unbound: UnboundBreakpoint[] = [];
+
+    for (const [file, breakpoints] of session.breakpoints) {
+      let result: BreakpointInfo[];
+      try {
+        result = await this.setBreakpointsInternal(session, file, breakpoints);
+      } catch (error) {
+        // e.g. the file was deleted; keep the breakpoints for the next run
+        result = breakpoints.map((bp) => ({
+          ...bp,
+          verified: false,
+          message: error instanceof Error ? error.message : String(error)
+        }));
+        session.breakpoints.set(file, result);
+      }
+
+      for (const bp of result.filter((b) => !b.verified)) {
+        unbound.push({
+          kind: 'line',
+          location: `${file}:${bp.requestedLine ?? bp.line}`,
+          message: bp.message
+        });
+      }
+    }
+
+    if (session.functionBreakpoints.length > 0) {
+      try {
+        const result = await this.setFunctionBreakpointsInternal(session);
+        for (const bp of result.filter((b) => !b.verified)) {
+          unbound.push({ kind: 'function', location: bp.name, message: bp.message });
+        }
+      } catch (error) {
+        for (const bp of session.functionBreakpoints) {
+          unbound.push({
+            kind: 'function',
+            location: bp.name,
+            message: error instanceof Error ? error.message : String(error)
+          });
+        }
+      }
+    }
+
+    return unbound;
+  }
+
+  /**
+   * Summarize replayed breakpoints that did not bind, for start/attach messages
+   */
+  private describeUnbound(unbound: UnboundBreakpoint[]): string {
+    if (unbound.length === 0) {
+      return '';
+    }
+    const locations = unbound.map((bp) => bp.location).join(', ');
+    return `. ${unbound.length} saved breakpoint(s) could not be bound: ${locations}`;
+  }
+
+  /**
+   * Swap the DAP client of a session, moving event handling to the new one
+   */
+  private replaceClient(sessionId: string, session: SessionData, client: DapClient): void {
+    session.client.removeAllListeners();
+    session.client = client;
+    this.setupEventHandlers(sessionId, client);
+  }
+
+  /**
+   * Point a session at a DAP server that is already listening (e.g.
+   * `dlv dap --listen` in a container) instead of spawning a local adapter
+   */
+  private connectRemote(sessionId: string, session: SessionData, host: string | undefined, port: number): void {
+    const client = new DapClient({
+      command: '',
+      args: [],
+      mode: 'connect',
+      host,
+      port,
+      trace: session.trace
+    });
+    this.replaceClient(sessionId, session, client);
+  }
+
+  /**
+   * Wait for the initialized event from the debug adapter
    */
   private waitForInitialized(client: DapClient, timeout: number = 10000): Promise<void> {
     return new Promise((resolve, reject) => {
+      const cleanup = () => {
+        clearTimeout(timer);
+        client.off('initialized', onInitialized);
+        client.off('error', onError);
+        client.off('adapterExit', onExit);
+      };
+
       const timer = setTimeout(() => {
+        cleanup();
         reject(new Error('Timeout waiting for initialized event'));
       }, timeout);
 
-      client.once('initialized', () => {
-        clearTimeout(timer);
+      const onInitialized = () => {
+        cleanup();
         resolve();
+      };
+
+      // A rejected launch/attach surfaces as an error event (see launchAsync),
+      // so report the adapter's message rather than a generic timeout
+      const onError = (error: Error) => {
+        cleanup();
+        reject(error);
+      };
+
+      const onExit = (_code: number | null, reason?: string) => {
+        cleanup();
+        reject(new Error(reason ?? 'Debug adapter exited before initializing'));
+      };
+
+      client.once('initialized', onInitialized);
+      client.once('error', onError);
+      client.once('adapterExit', onExit);
+    });
+  }
+
+  /**
+   * Set a breakpoint
+   */
+  async setBreakpoint(
+    sessionId: string,
+    request: SetBreakpointRequest
+  ): Promise<{ success: boolean; breakpoint?: BreakpointInfo; conditionIgnored?: boolean; message?: string }> {
+    const session = this.getSession(sessionId);
+    const { file, line } = request;
+
+    await this.waitForHandshake(sessionId);
+
+    // Normalize file path
+    const normalizedFile = path.resolve(file);
+    const existingBreakpoints = this.storeBreakpoint(session, normalizedFile, request);
+
+    // If session is active, send to adapter
+    if (
+      session.info.state === SessionState.READY ||
+      session.info.state === SessionState.RUNNING ||
+      session.info.state === SessionState.PAUSED
+    ) {
+      const result = await this.setBreakpointsInternal(
+        session,
+        normalizedFile,
+        existingBreakpoints
+      );
+      const bp = result.find((b) => b.requestedLine === line);
+      return { ...this.describeBinding(session, normalizedFile, line, bp), breakpoint: bp };
+    }
+
+    // Return pending breakpoint
+    return {
+      success: true,
+      breakpoint: existingBreakpoints.find((bp) => bp.requestedLine === line),
+      message: this.describePending(session)
+    };
+  }
+
+  /**
+   * Set several breakpoints at once, sending one setBreakpoints request per
+   * file instead of one per breakpoint
+   */
+  async setBreakpoints(
+    sessionId: string,
+    requests: SetBreakpointRequest[]
+  ): Promise<{ success: boolean; results: BreakpointSetResult[]; message: string }> {
+    const session = this.getSession(sessionId);
+
+    if (requests.length === 0) {
+      return { success: false, results: [], message: 'No breakpoints given' };
+    }
+
+    await this.waitForHandshake(sessionId);
+
+    // Later entries for the same line override earlier ones
+    const byFile = new Map<string, BreakpointInfo[]>();
+    for (const request of requests) {
+      const normalizedFile = path.resolve(request.file);
+      byFile.set(normalizedFile, this.storeBreakpoint(session, normalizedFile, request));
+    }
+
+    const active =
+      session.info.state === SessionState.READY ||
+      session.info.state === SessionState.RUNNING ||
+      session.info.state === SessionState.PAUSED;
+
+    const bound = new Map<string, BreakpointInfo[]>();
+    const failed = new Map<string, string>();
+    if (active) {
+      await Promise.all(
+        [...byFile].map(async ([file, breakpoints]) => {
+          try {
+            bound.set(file, await this.setBreakpointsInternal(session, file, breakpoints));
+          } catch (error) {
+            failed.set(file, error instanceof Error ? error.message : String(error));
+          }
+        })
+      );
+    }
+
+    const results: BreakpointSetResult[] = requests.map(({ file, line }) => {
+      const normalizedFile = path.resolve(file);
+      const error = failed.get(normalizedFile);
+      if (error) {
+        return { file: normalizedFile, line, success: false, message: error };
+      }
+      if (!active) {
+        return {
+          file: normalizedFile,
+          line,
+          success: true,
+          breakpoint: byFile.get(normalizedFile)?.find((bp) => bp.requestedLine === line),
+          message: this.describePending(session)
+        };
+      }
+      const bp = bound.get(normalizedFile)?.find((b) => b.requestedLine === line);
+      return {
+        file: normalizedFile,
+        line,
+        ...this.describeBinding(session, normalizedFile, line, bp),
+        breakpoint: bp
+      };
+    });
+
+    const succeeded = results.filter((r) => r.success).length;
+    return {
+      success: succeeded === results.length,
+      results,
+      message: active
+        ? `${succeeded} of ${results.length} breakpoint(s) set`
+        : `${results.length} breakpoint(s) saved (will be verified when debugging starts)`
+    };
+  }
+
+  /**
+   * Add or update a line breakpoint in the session's stored list and
+   * return the file's breakpoints, ready to send to the adapter
+   */
+  private storeBreakpoint(
+    session: SessionData,
+    normalizedFile: string,
+    request: SetBreakpointRequest
+  ): BreakpointInfo[] {
+    const { line, condition, hitCondition, logMessage, dumpFile, trace, maxDumps } = request;
+
+    // Get existing breakpoints for this file
+    const existingBreakpoints = session.breakpoints.get(normalizedFile) ?? [];
+
+    // Check if breakpoint already exists at this line
+    const existingIndex = existingBreakpoints.findIndex(
+      (bp) => (bp.requestedLine ?? bp.line) === line
+    );
+    if (existingIndex !== -1) {
+      // Drop the tracepoint key of the old bound line; it is re-registered below
+      session.dumpBreakpoints.delete(`${normalizedFile}:${existingBreakpoints[existingIndex].line}`);
+      // Update existing breakpoint
+      existingBreakpoints[existingIndex] = {
+        ...existingBreakpoints[existingIndex],
+        condition,
+        hitCondition,
+        logMessage,
+        dumpFile,
+        trace,
+        maxDumps,
+        dumpCount: 0 // Reset count on update
+      };
+    } else {
+      // Add new breakpoint
+      existingBreakpoints.push({
+        id: 0, // Will be set by adapter
+        file: normalizedFile,
+        line,
+        requestedLine: line,
+        verified: false,
+        condition,
+        hitCondition,
+        logMessage,
+        dumpFile,
+        trace,
+        maxDumps,
+        dumpCount: 0
       });
+    }
+
+    session.breakpoints.set(normalizedFile, existingBreakpoints);
+
+    // Register tracepoint for auto-continue behavior
+    const bpKey = `${normalizedFile}:${line}`;
+    if (trace || dumpFile) {
+      // Store dumpFile path (or empty string if just tracing internally)
+      session.dumpBreakpoints.set(bpKey, dumpFile ?? '');
+    } else {
+      session.dumpBreakpoints.delete(bpKey);
+    }
+
+    return existingBreakpoints;
+  }
+
+  /**
+   * Explain how the adapter bound a requested breakpoint line
+   */
+  private describeBinding(
+    session: SessionData,
+    file: string,
+    line: number,
+    bp: BreakpointInfo | undefined
+  ): { success: boolean; conditionIgnored?: boolean; message?: string } {
+    if (!bp?.verified) {
+      const reason = bp?.message ? `: ${bp.message}` : '';
+      return {
+        success: false,
+        message:
+          `The adapter could not bind a breakpoint at ${file}:${line}${reason}. ` +
+          'The program will not stop here; pick a line with executable code.'
+      };
+    }
+
+    // Adapters accept conditions they cannot evaluate, so flag them here.
+    // The breakpoint is still installed and will stop, so this is not a failure.
+    const warning = this.getUnsupportedConditionWarning(session, bp);
+    const moved =
+      bp.line !== line
+        ? `Breakpoint moved from line ${line} to line ${bp.line} (nearest executable line)`
+        : undefined;
+    return {
+      success: true,
+      conditionIgnored: warning !== undefined || undefined,
+      message: [warning, moved, bp.message].filter(Boolean).join('. ') || undefined
+    };
+  }
+
+  /**
+   * Explain when a breakpoint saved before the adapter is ready gets applied
+   */
+  private describePending(session: SessionData): string {
+    return session.info.state === SessionState.INITIALIZING
+      ? 'Adapter is still initializing; breakpoint saved and will be applied once it is ready'
+      : 'Breakpoint set (will be verified when debugging starts)';
+  }
+
+  /**
+   * Internal method to set breakpoints via DAP
+   */
+  private setBreakpointsInternal(
+    session: SessionData,
+    file: string,
+    breakpoints: BreakpointInfo[]
+  ): Promise<BreakpointInfo[]> {
+    // setBreakpoints replaces a file's whole list, so updates are queued:
+    // a slow response must not overwrite the result of a newer request
+    const update = session.breakpointUpdates.then(() =>
+      this.sendFileBreakpoints(session, file, breakpoints)
+    );
+    session.breakpointUpdates = update.catch(() => {});
+    return update;
+  }
+
+  /**
+   * Send one file's breakpoints and merge the adapter's answer into the session
+   */
+  private async sendFileBreakpoints(
+    session: SessionData,
+    file: string,
+    breakpoints: BreakpointInfo[]
+  ): Promise<BreakpointInfo[]> {
+    const source: DebugProtocol.Source = { path: file };
+    const bpRequests: DebugProtocol.SourceBreakpoint[] = breakpoints.map((bp) => ({
+      line: bp.requestedLine ?? bp.line,
+      column: bp.column,
+      condition: bp.condition,
+      hitCondition: bp.hitCondition,
+      logMessage: bp.logMessage
+    }));
+
+    const result = await session.client.setBreakpoints(source, bpRequests);
+
+    // Merge adapter results into stored breakpoints, keeping the requested
+    // condition/trace settings that the adapter does not echo back
+    const merged = result.map((bp, index) => {
+      const requested = breakpoints[index];
+      if (!requested) {
+        return bp;
+      }
+      const requestedLine = requested.requestedLine ?? requested.line;
+
+      // Tracepoints are matched by the line the program actually stops on
+      const oldKey = `${file}:${requested.line}`;
+      if (bp.line !== requested.line && session.dumpBreakpoints.has(oldKey)) {
+        session.dumpBreakpoints.set(`${file}:${bp.line}`, session.dumpBreakpoints.get(oldKey)!);
+        session.dumpBreakpoints.delete(oldKey);
+      }
+
+      return {
+        ...requested,
+        id: bp.id,
+        line: bp.line,
+        requestedLine,
+        column: bp.column ?? requested.column,
+        verified: bp.verified,
+        message: bp.message
+      };
     });
+
+    session.breakpoints.set(file, merged);
+
+    return merged;
+  }
+
+  /**
+   * Describe conditions on a breakpoint that the adapter cannot honor
+   */
+  private getUnsupportedConditionWarning(
+    session: SessionData,
+    bp: BreakpointInfo
+  ): string | undefined {
+    const capabilities = session.client.getCapabilities();
+    if (!capabilities) {
+      return undefined;
+    }
+
+    const unsupported: string[] = [];
+    if (bp.condition && !capabilities.supportsConditionalBreakpoints) {
+      unsupported.push(`condition '${bp.condition}'`);
+    }
+    if (bp.hitCondition && !capabilities.supportsHitConditionalBreakpoints) {
+      unsupported.push(`hit condition '${bp.hitCondition}'`);
+    }
+    if (bp.logMessage && !capabilities.supportsLogPoints) {
+      unsupported.push('log messages');
+    }
+
+    if (unsupported.length === 0) {
+      return undefined;
+    }
+    return `${session.adapter.name} does not support ${unsupported.join(' or ')}; the breakpoint will stop unconditionally`;
+  }
+
+  /**
+   * Set a logpoint: the adapter interpolates {expression} placeholders in
+   * the message and prints it as output without stopping
+   */
+  async setLogpoint(
+    sessionId: string,
+    request: { file: string; line: number; message: string; condition?: string; hitCondition?: string }
+  ): Promise<{ success: boolean; breakpoint?: BreakpointInfo; message?: string }> {
+    const session = this.getSession(sessionId);
+
+    if (!request.message) {
+      return { success: false, message: 'A logpoint needs a non-empty message' };
+    }
+
+    const capabilities = session.client.getCapabilities();
+    if (capabilities && !capabilities.supportsLogPoints) {
+      return {
+        success: false,
+        message: `${session.adapter.name} does not support logpoints. Use set_breakpoint with trace: true to collect values without stopping.`
+      };
+    }
+
+    return this.setBreakpoint(sessionId, {
+      file: request.file,
+      line: request.line,
+      condition: request.condition,
+      hitCondition: request.hitCondition,
+      logMessage: request.message
+    });
+  }
+
+  /**
+   * Set a breakpoint on entry to a function, by name
+   */
+  async setFunctionBreakpoint(
+    sessionId: string,
+    request: SetFunctionBreakpointRequest
+  ): Promise<{ success: boolean; breakpoint?: FunctionBreakpointInfo; message?: string }> {
+    const session = this.getSession(sessionId);
+    const { name, condition, hitCondition } = request;
+
+    await this.waitForHandshake(sessionId);
+
+    const capabilities = session.client.getCapabilities();
+    if (capabilities && !capabilities.supportsFunctionBreakpoints) {
+      return {
+        success: false,
+        message: `${session.adapter.name} does not support function breakpoints`
+      };
+    }
+
+    // Replace any existing breakpoint on the same function
+    const breakpoint: FunctionBreakpointInfo = {
+      id: 0, // Will be set by adapter
+      name,
+      condition,
+      hitCondition,
+      verified: false
+    };
+    const existingIndex = session.functionBreakpoints.findIndex((bp) => bp.name === name);
+    if (existingIndex !== -1) {
+      session.functionBreakpoints[existingIndex] = breakpoint;
+    } else {
+      session.functionBreakpoints.push(breakpoint);
+    }
+
+    // If session is active, send to adapter
+    if (
+      session.info.state === SessionState.READY ||
+      session.info.state === SessionState.RUNNING ||
+      session.info.state === SessionState.PAUSED
+    ) {
+      const result = await this.setFunctionBreakpointsInternal(session);
+      const bp = result.find((b) => b.name === name);
+      if (!bp?.verified) {
+        return {
+          success: false,
+          breakpoint: bp,
+          message: bp?.message ?? `Could not resolve function '${name}'`
+        };
+      }
+      return {
+        success: true,
+        breakpoint: bp,
+        message: bp.file ? `Bound to ${bp.file}:${bp.line}` : bp.message
+      };
+    }
+
+    // Return pending breakpoint
+    return {
+      success: true,
+      breakpoint,
+      message: 'Function breakpoint set (will be resolved when debugging starts)'
+    };
+  }
+
+  /**
+   * Internal method to send all function breakpoints via DAP
+   * (setFunctionBreakpoints replaces the whole set)
+   */
+  private async setFunctionBreakpointsInternal(
+    session: SessionData
+  ): Promise<FunctionBreakpointInfo[]> {
+    const requested = session.functionBreakpoints;
+    const result = await session.client.setFunctionBreakpoints(
+      requested.map((bp) => ({
+        name: bp.name,
+        condition: bp.condition,
+        hitCondition: bp.hitCondition
+      }))
+    );
+
+    const merged = requested.map((bp, index) => {
+      const resolved = result[index];
+      if (!resolved) {
+        return bp;
+      }
+      return {
+        ...bp,
+        id: resolved.id,
+        verified: resolved.verified,
+        file: resolved.file || undefined,
+        line: resolved.line || undefined,
+        message: resolved.message
+      };
+    });
+
+    session.functionBreakpoints = merged;
+    return merged;
+  }
+
+  /**
+   * Set a watchpoint that stops when a variable is accessed or changed
+   */
+  async setWatchpoint(
+    sessionId: string,
+    request: SetWatchpointRequest
+  ): Promise<{ success: boolean; watchpoint?: DataBreakpointInfo; message?: string }> {
+    const session = this.getSession(sessionId);
+    const { name, condition, hitCondition } = request;
+    const accessType = request.accessType ?? 'write';
+
+    const capabilities = session.client.getCapabilities();
+    if (!capabilities) {
+      return { success: false, message: 'Adapter not initialized yet; start debugging first' };
+    }
+    if (!capabilities.supportsDataBreakpoints) {
+      return {
+        success: false,
+        message: `${session.adapter.name} does not support data breakpoints (watchpoints)`
+      };
+    }
+    if (session.info.state !== SessionState.PAUSED) {
+      return { success: false, message: 'Program must be paused to resolve a watchpoint variable' };
+    }
+
+    // Resolve against the scope that holds the variable; otherwise let the
+    // adapter treat the name as an expression in the frame
+    const fid = request.frameId ?? session.currentFrameId;
+    const containerRef = await this.findVariableContainer(session, name, fid);
+    const info = containerRef !== undefined
+      ? await session.client.dataBreakpointInfo(name, containerRef)
+      : await session.client.dataBreakpointInfo(name, undefined, fid);
+
+    if (!info.dataId) {
+      return {
+        success: false,
+        message: `Cannot watch '${name}': ${info.description}`
+      };
+    }
+    if (info.accessTypes && !info.accessTypes.includes(accessType)) {
+      return {
+        success: false,
+        message: `Cannot watch '${name}' for ${accessType} access (supported: ${info.accessTypes.join(', ')})`
+      };
+    }
+
+    const watchpoint: DataBreakpointInfo = {
+      id: 0, // Will be set by adapter
+      dataId: info.dataId,
+      name,
+      description: info.description,
+      accessType,
+      condition,
+      hitCondition,
+      verified: false
+    };
+    session.dataBreakpoints = session.dataBreakpoints.filter((bp) => bp.dataId !== info.dataId);
+    session.dataBreakpoints.push(watchpoint);
+
+    const result = await this.setDataBreakpointsInternal(session);
+    const bp = result.find((b) => b.dataId === info.dataId);
+    return {
+      success: bp?.verified ?? false,
+      watchpoint: bp,
+      message: bp?.verified ? undefined : bp?.message ?? 'Watchpoint not verified'
+    };
+  }
+
+  /**
+   * Internal method to send all data breakpoints via DAP
+   */
+  private async setDataBreakpointsInternal(session: SessionData): Promise<DataBreakpointInfo[]> {
+    const requested = session.dataBreakpoints;
+    const result = await session.client.setDataBreakpoints(
+      requested.map((bp) => ({
+        dataId: bp.dataId,
+        accessType: bp.accessType,
+        condition: bp.condition,
+        hitCondition: bp.hitCondition
+      }))
+    );
+
+    session.dataBreakpoints = requested.map((bp, index) => {
+      const resolved = result[index];
+      if (!resolved) {
+        return bp;
+      }
+      return {
+        ...bp,
+        id: resolved.id ?? 0,
+        verified: resolved.verified,
+        message: resolved.message
+      };
+    });
+    return session.dataBreakpoints;
+  }
+
+  /**
+   * Find the variablesReference of the scope in a frame that declares a variable
+   */
+  private async findVariableContainer(
+    session: SessionData,
+    name: string,
+    frameId: number
+  ): Promise<number | undefined> {
+    const scopes = await session.client.scopes(frameId);
+    for (const scope of scopes) {
+      if (scope.expensive) {
+        continue;
+      }
+      const variables = await session.client.variables(scope.variablesReference);
+      if (variables.some((v) => v.name === name)) {
+        return scope.variablesReference;
+      }
+    }
+    return undefined;
+  }
+
+  /**
+   * Assign a new value to a variable while paused. Rejected assignments
+   * (e.g. type mismatches) return the adapter's error.
+   */
+  async setVariable(sessionId: string, request: SetVariableRequest): Promise<SetVariableResult> {
+    const session = this.getSession(sessionId);
+    const { name, value } = request;
+
+    const capabilities = session.client.getCapabilities();
+    if (!capabilities?.supportsSetVariable && !capabilities?.supportsSetExpression) {
+      return {
+        success: false,
+        name,
+        message: `${session.adapter.name} does not support changing variables`
+      };
+    }
+    if (session.info.state !== SessionState.PAUSED) {
+      return { success: false, name, message: 'Program must be paused to set a variable' };
+    }
+
+    const fid = request.frameId ?? session.currentFrameId;
+    try {
+      const containerRef = request.variablesReference ??
+        (capabilities.supportsSetVariable
+          ? await this.findVariableContainer(session, name, fid)
+          : undefined);
+
+      let result: SetVariableResult;
+      if (containerRef !== undefined && capabilities.supportsSetVariable) {
+        const body = await session.client.setVariable(containerRef, name, value);
+        result = { success: true, name, ...body };
+      } else if (capabilities.supportsSetExpression) {
+        const body = await session.client.setExpression(name, value, fid);
+        result = { success: true, name, ...body };
+      } else {
+        return { success: false, name, message: `Variable '${name}' not found in frame ${fid}` };
+      }
+
+      // Keep the cached stop context consistent with the new value
+      const cached = session.lastStopContext?.variables.find((v) => v.name === name);
+      if (cached && result.value !== undefined) {
+        cached.value = result.value;
+      }
+
+      return result;
+    } catch (error) {
+      return {
+        success: false,
+        name,
+        message: `Adapter rejected the assignment: ${error instanceof Error ? error.message : error}`
+      };
+    }
+  }
+
+  /**
+   * List all function breakpoints
+   */
+  listFunctionBreakpoints(sessionId: string): FunctionBreakpointInfo[] {
+    return this.getSession(sessionId).functionBreakpoints;
+  }
+
+  /**
+   * List all watchpoints
+   */
+  listWatchpoints(sessionId: string): DataBreakpointInfo[] {
+    return this.getSession(sessionId).dataBreakpoints;
   }
 
   /**
-   * Set a breakpoint
+   * Choose which exceptions stop execution, replacing the previous filters.
+   * Filter IDs come from the adapter (see availableFilters in the result).
    */
-  async setBreakpoint(
+  async setExceptionBreakpoints(
     sessionId: string,
-    request: SetBreakpointRequest
-  ): Promise<{ success: boolean; breakpoint?: BreakpointInfo; message?: string }> {
+    filters: string[]
+  ): Promise<{
+    success: boolean;
+    filters: string[];
+    availableFilters: DebugProtocol.ExceptionBreakpointsFilter[];
+    message: string;
+  }> {
     const session = this.getSession(sessionId);
-    const { file, line, condition, hitCondition, logMessage, dumpFile, trace, maxDumps } = request;
-
-    // Normalize file path
-    const normalizedFile = path.resolve(file);
 
-    // Get existing breakpoints for this file
-    const existingBreakpoints = session.breakpoints.get(normalizedFile) ?? [];
+    await this.waitForHandshake(sessionId);
+    const capabilities = session.client.getCapabilities();
+    const availableFilters = this.getExceptionFilters(session);
 
-    // Check if breakpoint already exists at this line
-    const existingIndex = existingBreakpoints.findIndex((bp) => bp.line === line);
-    if (existingIndex !== -1) {
-      // Update existing breakpoint
-      existingBreakpoints[existingIndex] = {
-        ...existingBreakpoints[existingIndex],
-        condition,
-        hitCondition,
-        logMessage,
-        dumpFile,
-        trace,
-        maxDumps,
-        dumpCount: 0 // Reset count on update
+    // Capabilities are unknown until the adapter is initialized; replay on start
+    if (!capabilities) {
+      session.exceptionFilters = filters;
+      return {
+        success: true,
+        filters,
+        availableFilters,
+        message: 'Exception filters saved (will be applied when debugging starts)'
       };
-    } else {
-      // Add new breakpoint
-      existingBreakpoints.push({
-        id: 0, // Will be set by adapter
-        file: normalizedFile,
-        line,
-        verified: false,
-        condition,
-        hitCondition,
-        logMessage,
-        dumpFile,
-        trace,
-        maxDumps,
-        dumpCount: 0
-      });
     }
 
-    session.breakpoints.set(normalizedFile, existingBreakpoints);
+    if (availableFilters.length === 0) {
+      const note =
+        session.info.language === DebugLanguage.GO
+          ? ' Delve always stops on unrecovered panics and fatal runtime errors.'
+          : '';
+      return {
+        success: filters.length === 0,
+        filters: [],
+        availableFilters,
+        message: `${session.adapter.name} has no exception filters to configure.${note}`
+      };
+    }
 
-    // Register tracepoint for auto-continue behavior
-    const bpKey = `${normalizedFile}:${line}`;
-    if (trace || dumpFile) {
-      // Store dumpFile path (or empty string if just tracing internally)
-      session.dumpBreakpoints.set(bpKey, dumpFile ?? '');
-    } else {
-      session.dumpBreakpoints.delete(bpKey);
+    const unknown = filters.filter((f) => !availableFilters.some((af) => af.filter === f));
+    if (unknown.length > 0) {
+      return {
+        success: false,
+        filters: session.exceptionFilters,
+        availableFilters,
+        message: `Unknown exception filter(s): ${unknown.join(', ')}`
+      };
     }
 
-    // If session is active, send to adapter
+    session.exceptionFilters = filters;
     if (
       session.info.state === SessionState.READY ||
       session.info.state === SessionState.RUNNING ||
       session.info.state === SessionState.PAUSED
     ) {
-      const result = await this.setBreakpointsInternal(
-        session,
-        normalizedFile,
-        existingBreakpoints
-      );
-      const bp = result.find((b) => b.line === line);
-      return {
-        success: bp?.verified ?? false,
-        breakpoint: bp,
-        message: bp?.verified ? undefined : bp?.message ?? 'Breakpoint not verified'
-      };
+      await session.client.setExceptionBreakpoints(filters);
     }
 
-    // Return pending breakpoint
     return {
       success: true,
-      breakpoint: existingBreakpoints.find((bp) => bp.line === line),
-      message: 'Breakpoint set (will be verified when debugging starts)'
+      filters,
+      availableFilters,
+      message: filters.length > 0
+        ? `Breaking on: ${filters.join(', ')}`
+        : 'Exception breakpoints cleared'
     };
   }
 
   /**
-   * Internal method to set breakpoints via DAP
+   * Exception filters advertised by the adapter
    */
-  private async setBreakpointsInternal(
-    session: SessionData,
-    file: string,
-    breakpoints: BreakpointInfo[]
-  ): Promise<BreakpointInfo[]> {
-    const source: DebugProtocol.Source = { path: file };
-    const bpRequests: DebugProtocol.SourceBreakpoint[] = breakpoints.map((bp) => ({
-      line: bp.line,
-      column: bp.column,
-      condition: bp.condition,
-      hitCondition: bp.hitCondition,
-      logMessage: bp.logMessage
-    }));
-
-    const result = await session.client.setBreakpoints(source, bpRequests);
-
-    // Update stored breakpoints with results
-    session.breakpoints.set(file, result);
-
-    return result;
+  private getExceptionFilters(session: SessionData): DebugProtocol.ExceptionBreakpointsFilter[] {
+    return session.client.getCapabilities()?.exceptionBreakpointFilters ?? [];
   }
 
   /**
-   * Remove a breakpoint
+   * Remove a breakpoint by file+line or by the ID returned from set_breakpoint.
+   * Remaining breakpoints in the file are re-sent since setBreakpoints replaces the file's set.
    */
   async removeBreakpoint(
     sessionId: string,
-    file: string,
-    line: number
-  ): Promise<{ success: boolean; message: string }> {
+    request: RemoveBreakpointRequest
+  ): Promise<BreakpointChangeResult> {
     const session = this.getSession(sessionId);
-    const normalizedFile = path.resolve(file);
 
-    const breakpoints = session.breakpoints.get(normalizedFile);
-    if (!breakpoints) {
-      return { success: false, message: 'No breakpoints in this file' };
+    let file: string | undefined;
+    let index = -1;
+    if (request.breakpointId !== undefined) {
+      for (const [bpFile, breakpoints] of session.breakpoints) {
+        index = breakpoints.findIndex((bp) => bp.id === request.breakpointId);
+        if (index !== -1) {
+          file = bpFile;
+          break;
+        }
+      }
+    } else if (request.file && request.line !== undefined) {
+      file = path.resolve(request.file);
+      index = (session.breakpoints.get(file) ?? []).findIndex(
+        (bp) => bp.line === request.line || bp.requestedLine === request.line
+      );
+    } else {
+      return {
+        success: false,
+        message: 'Provide either breakpointId or file and line',
+        breakpoints: this.listBreakpoints(sessionId)
+      };
+    }
+
+    if (!file || index === -1) {
+      return {
+        success: false,
+        message: 'Breakpoint not found',
+        breakpoints: this.listBreakpoints(sessionId)
+      };
     }
 
-    const index = breakpoints.findIndex((bp) => bp.line === line);
-    if (index === -1) {
-      return { success: false, message: 'Breakpoint not found at this line' };
+    const breakpoints = session.breakpoints.get(file)!;
+    const [removed] = breakpoints.splice(index, 1);
+    session.dumpBreakpoints.delete(`${file}:${removed.line}`);
+
+    await this.syncFileBreakpoints(session, file, breakpoints);
+
+    return {
+      success: true,
+      message: `Breakpoint removed at ${file}:${removed.line}`,
+      breakpoints: this.listBreakpoints(sessionId)
+    };
+  }
+
+  /**
+   * Clear all breakpoints, or only those in one file
+   */
+  async clearBreakpoints(sessionId: string, file?: string): Promise<BreakpointChangeResult> {
+    const session = this.getSession(sessionId);
+    const files = file ? [path.resolve(file)] : [...session.breakpoints.keys()];
+
+    let cleared = 0;
+    for (const bpFile of files) {
+      const breakpoints = session.breakpoints.get(bpFile) ?? [];
+      for (const bp of breakpoints) {
+        session.dumpBreakpoints.delete(`${bpFile}:${bp.line}`);
+      }
+      cleared += breakpoints.length;
+
+      await this.syncFileBreakpoints(session, bpFile, []);
     }
 
-    breakpoints.splice(index, 1);
+    return {
+      success: true,
+      message: `Cleared ${cleared} breakpoint(s)`,
+      breakpoints: this.listBreakpoints(sessionId)
+    };
+  }
 
-    // Update in adapter if active
+  /**
+   * Store a file's breakpoint set and re-send it to the adapter if active
+   */
+  private async syncFileBreakpoints(
+    session: SessionData,
+    file: string,
+    breakpoints: BreakpointInfo[]
+  ): Promise<void> {
     if (
       session.info.state === SessionState.READY ||
       session.info.state === SessionState.RUNNING ||
       session.info.state === SessionState.PAUSED
     ) {
-      await this.setBreakpointsInternal(session, normalizedFile, breakpoints);
+      await this.setBreakpointsInternal(session, file, breakpoints);
     }
 
-    return { success: true, message: 'Breakpoint removed' };
+    if (breakpoints.length === 0) {
+      session.breakpoints.delete(file);
+    }
   }
 
   /**
@@ -645,24 +2485,68 @@ export class SessionManager extends EventEmitter {
   }
 
   /**
-   * Continue execution
-   * @param collectHits - If set, collect this many breakpoint hits before returning (auto-continues at each hit)
+   * Get buffered debuggee output
+   * @param follow - Only return output not returned by a previous call (default: true).
+   * With a category, only that category's output counts as read.
+   */
+  getOutput(
+    sessionId: string,
+    options?: { follow?: boolean; category?: DebugOutput['category'] }
+  ): {
+    output: BufferedOutput[];
+    text: string;
+    dropped: number;
+    state: SessionState;
+    exitCode?: number;
+  } {
+    const session = this.getSessionOrFinished(sessionId);
+    const follow = options?.follow ?? true;
+    const category = options?.category;
+
+    // Reading one category must not consume unread output of the others
+    const readSeq = (c: string) =>
+      Math.max(session.outputReadSeq, session.outputCategoryReadSeq.get(c) ?? 0);
+
+    let entries = category
+      ? session.outputBuffer.filter((o) => o.category === category)
+      : session.outputBuffer;
+    if (follow) {
+      entries = entries.filter((o) => o.seq > readSeq(o.category));
+    }
+
+    // Entries that fell out of the ring buffer before they were read
+    const firstSeq = session.outputBuffer[0]?.seq ?? session.outputSeq + 1;
+    const fromSeq = follow ? (category ? readSeq(category) : session.outputReadSeq) + 1 : 1;
+    const dropped = Math.max(0, firstSeq - fromSeq);
+
+    if (category) {
+      session.outputCategoryReadSeq.set(category, session.outputSeq);
+    } else {
+      session.outputReadSeq = session.outputSeq;
+      session.outputCategoryReadSeq.clear();
+    }
+
+    return {
+      output: entries,
+      text: entries.map((o) => o.output).join(''),
+      dropped,
+      state: session.info.state,
+      exitCode: session.info.exitCode
+    };
+  }
+
+  /**
+   * Continue execution. With waitForBreakpoint or a timeout, waits for the
+   * next stop or exit and reports 'running' if neither happens in time.
    */
   async continue(
     sessionId: string,
     threadId?: number,
     options?: { waitForBreakpoint?: boolean; timeout?: number; collectHits?: number }
-  ): Promise<{
-    success: boolean;
-    state: SessionState;
-    message: string;
-    stoppedAt?: StackFrame;
-    variables?: Variable[];
-    traces?: TracePoint[];
-  }> {
+  ): Promise<RunResult> {
     const session = this.getSession(sessionId);
     const tid = threadId ?? session.currentThreadId;
-    const waitForBreakpoint = options?.waitForBreakpoint ?? false;
+    const waitForStop = options?.waitForBreakpoint ?? (options?.timeout !== undefined);
     const timeout = options?.timeout ?? 30000; // 30s default
     const collectHits = options?.collectHits;
 
@@ -679,6 +2563,8 @@ export class SessionManager extends EventEmitter {
             break;
           }
 
+          // Mark running so waitForPause does not return the previous stop
+          this.updateState(sessionId, SessionState.RUNNING);
           await session.client.continue(tid);
 
           // Wait for pause with remaining timeout
@@ -706,59 +2592,275 @@ export class SessionManager extends EventEmitter {
         }
 
         return {
-          success: true,
-          state: session.info.state,
+          ...this.getRunOutcome(session, timeout),
           message: `Collected ${hitCount} breakpoint hit(s)`,
           traces: session.collectedTraces
         };
       }
 
       // Standard continue
+      this.updateState(sessionId, SessionState.RUNNING);
       await session.client.continue(tid);
 
-      if (waitForBreakpoint) {
+      if (waitForStop) {
         await this.waitForPause(sessionId, timeout);
-        return {
-          success: true,
-          state: session.info.state,
-          message: session.info.state === SessionState.PAUSED
-            ? 'Hit breakpoint'
-            : 'Execution continued (no breakpoint hit)',
-          stoppedAt: session.lastStopContext?.stackFrame,
-          variables: session.lastStopContext?.variables
-        };
+        return this.getRunOutcome(session, timeout);
+      }
+
+      return {
+        success: true,
+        state: session.info.state,
+        status: 'running',
+        message: 'Execution continued'
+      };
+    } catch (error) {
+      // The continue was rejected, so the program is still where it was
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
+      }
+      return {
+        success: false,
+        state: session.info.state,
+        status: session.info.state === SessionState.PAUSED ? 'stopped' : 'running',
+        message: `Continue failed: ${error}`
+      };
+    }
+  }
+
+  /**
+   * Keep continuing through breakpoint stops until an expression is true in
+   * the stopped frame. Exceptions, pauses and program exit end the run early.
+   */
+  async continueUntil(
+    sessionId: string,
+    expression: string,
+    options?: { threadId?: number; maxIterations?: number; timeout?: number }
+  ): Promise<ContinueUntilResult> {
+    const session = this.getSession(sessionId);
+    const maxIterations = options?.maxIterations ?? DEFAULT_CONTINUE_UNTIL_ITERATIONS;
+    const timeout = options?.timeout ?? 30000;
+    const startTime = Date.now();
+
+    const state = session.info.state;
+    if (
+      state !== SessionState.PAUSED &&
+      state !== SessionState.RUNNING &&
+      state !== SessionState.READY
+    ) {
+      return {
+        success: false,
+        state,
+        status: 'exited',
+        conditionMet: false,
+        stops: 0,
+        message: `Cannot continue: program is not running (state: ${state})`
+      };
+    }
+
+    let stops = 0;
+    let last: ExpressionEvaluation | undefined;
+    try {
+      while (stops < maxIterations) {
+        const remaining = timeout - (Date.now() - startTime);
+        if (remaining <= 0) {
+          break;
+        }
+
+        if (session.info.state === SessionState.PAUSED) {
+          // Mark running so waitForPause does not return the previous stop
+          this.updateState(sessionId, SessionState.RUNNING);
+          await session.client.continue(options?.threadId ?? session.currentThreadId);
+        }
+        await this.waitForPause(sessionId, remaining);
+
+        if (session.info.state !== SessionState.PAUSED) {
+          break;
+        }
+        stops++;
+
+        // Only breakpoint hits are stepping stones; anything else needs attention
+        const reason = session.lastStop?.reason;
+        if (reason !== undefined && !BREAKPOINT_STOP_REASONS.has(reason)) {
+          return {
+            ...this.getRunOutcome(session, timeout),
+            conditionMet: false,
+            stops,
+            message: `Stopped (${reason}) before the condition became true`
+          };
+        }
+
+        last = await this.evaluateExpression(sessionId, expression, undefined, 'watch');
+        // A typo or an out-of-scope name would otherwise run past every breakpoint
+        if (!last.success) {
+          return {
+            ...this.getRunOutcome(session, timeout),
+            success: false,
+            conditionMet: false,
+            stops,
+            message: `Could not evaluate ${expression} at stop ${stops}; paused there: ${last.error}`
+          };
+        }
+        if (isTruthy(last.result ?? '')) {
+          return {
+            ...this.getRunOutcome(session, timeout),
+            conditionMet: true,
+            stops,
+            value: last.result,
+            message: `${expression} is ${last.result} after ${stops} stop(s)`
+          };
+        }
+      }
+    } catch (error) {
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
       }
+      return {
+        success: false,
+        state: session.info.state,
+        status: session.info.state === SessionState.PAUSED ? 'stopped' : 'running',
+        conditionMet: false,
+        stops,
+        message: `Continue failed: ${error instanceof Error ? error.message : error}`
+      };
+    }
+
+    const outcome = this.getRunOutcome(session, timeout);
+    const lastResult = last ? `last value: ${last.result}` : undefined;
+    let message: string;
+    if (outcome.status === 'stopped') {
+      message = `${expression} was still false after ${stops} stop(s); paused at the last one`;
+    } else if (outcome.status === 'exited') {
+      message = `${outcome.message} before ${expression} became true`;
+    } else {
+      message = `${expression} did not become true within ${timeout}ms; program is still running`;
+    }
+
+    return {
+      ...outcome,
+      success: false,
+      conditionMet: false,
+      stops,
+      value: last?.result,
+      message: lastResult ? `${message} (${lastResult})` : message
+    };
+  }
+
+  /**
+   * Wait for a running program to stop or exit, e.g. after continue timed out
+   */
+  async waitForStop(sessionId: string, timeout: number = 30000): Promise<RunResult> {
+    const session = this.getSession(sessionId);
+
+    if (
+      session.info.state === SessionState.RUNNING ||
+      session.info.state === SessionState.READY ||
+      session.info.state === SessionState.INITIALIZING
+    ) {
+      await this.waitForPause(sessionId, timeout);
+    }
+
+    return this.getRunOutcome(session, timeout);
+  }
+
+  /**
+   * Describe where a run ended up: stopped, exited, or still running
+   */
+  private getRunOutcome(session: SessionData, timeout: number): RunResult {
+    const state = session.info.state;
+
+    if (state === SessionState.PAUSED) {
+      return {
+        success: true,
+        state,
+        status: 'stopped',
+        message: `Stopped (${session.lastStop?.reason ?? 'unknown'})`,
+        stoppedAt: session.lastStopContext?.stackFrame,
+        stopReason: session.lastStop,
+        variables: session.lastStopContext?.variables
+      };
+    }
+
+    if (state === SessionState.ERROR) {
+      return {
+        success: false,
+        state,
+        status: 'exited',
+        termination: session.info.termination,
+        message: session.info.error ?? 'Debug session failed'
+      };
+    }
+
+    if (state === SessionState.TERMINATED) {
+      return {
+        success: true,
+        state,
+        status: 'exited',
+        exitCode: session.info.exitCode,
+        termination: session.info.termination,
+        message: `Program exited with code ${session.info.exitCode ?? 'unknown'}`
+      };
+    }
+
+    return {
+      success: true,
+      state,
+      status: 'running',
+      message: `No stop within ${timeout}ms; program is still running (use wait_for_stop to keep waiting or pause to interrupt it)`
+    };
+  }
+
+  /**
+   * Pause execution
+   */
+  async pause(
+    sessionId: string,
+    threadId?: number,
+    timeout: number = 5000
+  ): Promise<StepResult> {
+    const session = this.getSession(sessionId);
+    const tid = threadId ?? session.currentThreadId;
 
+    if (session.info.state === SessionState.PAUSED) {
       return {
         success: true,
         state: session.info.state,
-        message: 'Execution continued'
+        message: 'Already paused',
+        stoppedAt: session.lastStopContext?.stackFrame,
+        stopReason: session.lastStop,
+        variables: session.lastStopContext?.variables
       };
-    } catch (error) {
+    }
+    if (session.info.state !== SessionState.RUNNING) {
       return {
         success: false,
         state: session.info.state,
-        message: `Continue failed: ${error}`
+        message: `Cannot pause: program is not running (state: ${session.info.state})`
       };
     }
-  }
-
-  /**
-   * Pause execution
-   */
-  async pause(
-    sessionId: string,
-    threadId?: number
-  ): Promise<{ success: boolean; state: SessionState; message: string }> {
-    const session = this.getSession(sessionId);
-    const tid = threadId ?? session.currentThreadId;
 
     try {
       await session.client.pause(tid);
+      await this.waitForPause(sessionId, timeout);
+
+      if (session.info.state !== SessionState.PAUSED) {
+        return {
+          success: false,
+          state: session.info.state,
+          message: session.info.state === SessionState.TERMINATED
+            ? 'Program exited before it could be paused'
+            : session.info.state === SessionState.ERROR
+              ? session.info.error ?? 'Debug session failed'
+              : `Pause requested but the program did not stop within ${timeout}ms`
+        };
+      }
+
       return {
         success: true,
         state: session.info.state,
-        message: 'Execution paused'
+        message: 'Execution paused',
+        stoppedAt: session.lastStopContext?.stackFrame,
+        stopReason: session.lastStop,
+        variables: session.lastStopContext?.variables
       };
     } catch (error) {
       return {
@@ -769,6 +2871,35 @@ export class SessionManager extends EventEmitter {
     }
   }
 
+  /**
+   * Wait until the adapter handshake finishes, returning false on timeout.
+   * Requests sent mid-handshake can be lost, so calls that may arrive while
+   * the adapter is still configuring wait here first.
+   */
+  private waitForHandshake(sessionId: string, timeoutMs: number = 10000): Promise<boolean> {
+    return new Promise((resolve) => {
+      if (this.sessions.get(sessionId)?.info.state !== SessionState.INITIALIZING) {
+        resolve(true);
+        return;
+      }
+
+      const timeout = setTimeout(() => {
+        this.off('sessionStateChanged', handler);
+        resolve(false);
+      }, timeoutMs);
+
+      const handler = (changedSessionId: string, newState: SessionState) => {
+        if (changedSessionId === sessionId && newState !== SessionState.INITIALIZING) {
+          clearTimeout(timeout);
+          this.off('sessionStateChanged', handler);
+          resolve(true);
+        }
+      };
+
+      this.on('sessionStateChanged', handler);
+    });
+  }
+
   /**
    * Wait for session to pause (with timeout)
    */
@@ -780,107 +2911,553 @@ export class SessionManager extends EventEmitter {
         return;
       }
 
-      // Already paused
-      if (session.info.state === SessionState.PAUSED) {
+      // Already paused, or will never pause again
+      if (
+        session.info.state === SessionState.PAUSED ||
+        session.info.state === SessionState.TERMINATED ||
+        session.info.state === SessionState.ERROR
+      ) {
         resolve();
         return;
       }
 
       const timeout = setTimeout(() => {
         this.off('stopped', handler);
+        this.off('sessionTerminated', handler);
         resolve(); // Don't fail, just return without context
       }, timeoutMs);
 
+      // Program exit also ends the wait; callers check the state
       const handler = (stoppedSessionId: string) => {
         if (stoppedSessionId === sessionId) {
           clearTimeout(timeout);
           this.off('stopped', handler);
+          this.off('sessionTerminated', handler);
           // Small delay to let lastStopContext be populated
           setTimeout(resolve, 50);
         }
       };
 
       this.on('stopped', handler);
+      this.on('sessionTerminated', handler);
     });
   }
 
+  /**
+   * Run until a line is reached using a temporary breakpoint, which is
+   * removed afterwards. Existing breakpoints still stop execution first.
+   */
+  async runToLine(
+    sessionId: string,
+    file: string,
+    line: number,
+    options?: { threadId?: number; timeout?: number }
+  ): Promise<StepResult> {
+    const session = this.getSession(sessionId);
+    const tid = options?.threadId ?? session.currentThreadId;
+    const timeout = options?.timeout ?? 30000;
+    const normalizedFile = path.resolve(file);
+
+    if (session.info.state !== SessionState.PAUSED) {
+      return {
+        success: false,
+        state: session.info.state,
+        message: `Cannot run to line: program is not paused (state: ${session.info.state})`
+      };
+    }
+
+    // Reuse a user breakpoint on the target line instead of adding a duplicate
+    const fileBreakpoints = session.breakpoints.get(normalizedFile) ?? [];
+    const existing = fileBreakpoints.find((bp) => (bp.requestedLine ?? bp.line) === line);
+    let targetLine = existing?.line ?? line;
+
+    try {
+      if (!existing) {
+        const result = await this.setBreakpointsInternal(session, normalizedFile, [
+          ...fileBreakpoints,
+          { id: 0, file: normalizedFile, line, requestedLine: line, verified: false }
+        ]);
+        const temp = result.find((bp) => bp.requestedLine === line);
+        if (!temp?.verified) {
+          return {
+            success: false,
+            state: session.info.state,
+            message: `Cannot run to ${normalizedFile}:${line}: ${temp?.message ?? 'the adapter could not bind a breakpoint there'}`
+          };
+        }
+        targetLine = temp.line;
+      }
+
+      this.updateState(sessionId, SessionState.RUNNING);
+      await session.client.continue(tid);
+      await this.waitForPause(sessionId, timeout);
+    } catch (error) {
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
+      }
+      return {
+        success: false,
+        state: session.info.state,
+        message: `Run to line failed: ${error instanceof Error ? error.message : error}`
+      };
+    } finally {
+      if (!existing) {
+        await this.removeTemporaryBreakpoint(session, normalizedFile, line);
+      }
+    }
+
+    const state = session.info.state;
+    if (state === SessionState.TERMINATED) {
+      return {
+        success: false,
+        state,
+        message: `Program exited (code ${session.info.exitCode ?? 'unknown'}) before reaching ${normalizedFile}:${line}`
+      };
+    }
+    if (state === SessionState.ERROR) {
+      return {
+        success: false,
+        state,
+        message: session.info.error ?? 'Debug session failed'
+      };
+    }
+    if (state !== SessionState.PAUSED) {
+      return {
+        success: false,
+        state,
+        message: `Line ${line} was not reached within ${timeout}ms; the program is still running`
+      };
+    }
+
+    const frame = session.lastStopContext?.stackFrame;
+    const reached = frame !== undefined && path.resolve(frame.file) === normalizedFile && frame.line === targetLine;
+    return {
+      success: true,
+      state,
+      message: reached
+        ? `Reached ${normalizedFile}:${targetLine}`
+        : `Stopped at ${frame?.file}:${frame?.line} before reaching line ${line}`,
+      stoppedAt: frame,
+      stopReason: session.lastStop,
+      variables: session.lastStopContext?.variables
+    };
+  }
+
+  /**
+   * Drop a run_to_line breakpoint, leaving the user's breakpoints intact
+   */
+  private async removeTemporaryBreakpoint(
+    session: SessionData,
+    file: string,
+    line: number
+  ): Promise<void> {
+    const remaining = (session.breakpoints.get(file) ?? []).filter(
+      (bp) => bp.requestedLine !== line
+    );
+    if (remaining.length === 0) {
+      session.breakpoints.delete(file);
+    } else {
+      session.breakpoints.set(file, remaining);
+    }
+
+    try {
+      await this.syncFileBreakpoints(session, file, remaining);
+    } catch {
+      // Program may have terminated
+    }
+  }
+
   /**
    * Step in
    */
   async stepIn(
     sessionId: string,
     threadId?: number
-  ): Promise<{ success: boolean; state: SessionState; stoppedAt?: StackFrame; variables?: Variable[] }> {
+  ): Promise<StepResult> {
+    return this.step(sessionId, 'in', threadId);
+  }
+
+  /**
+   * Step over
+   */
+  async stepOver(
+    sessionId: string,
+    threadId?: number
+  ): Promise<StepResult> {
+    return this.step(sessionId, 'over', threadId);
+  }
+
+  /**
+   * Step out
+   */
+  async stepOut(
+    sessionId: string,
+    threadId?: number
+  ): Promise<StepResult> {
+    return this.step(sessionId, 'out', threadId);
+  }
+
+  /**
+   * Step a single machine instruction
+   */
+  async stepInstruction(sessionId: string, threadId?: number): Promise<StepResult> {
+    const session = this.getSession(sessionId);
+    if (!session.client.getCapabilities()?.supportsSteppingGranularity) {
+      return {
+        success: false,
+        state: session.info.state,
+        message: `${session.adapter.name} does not support instruction stepping`
+      };
+    }
+    return this.step(sessionId, 'in', threadId, 'instruction');
+  }
+
+  /**
+   * List where execution can jump to on a source line (set next statement)
+   */
+  async getGotoTargets(
+    sessionId: string,
+    file: string,
+    line: number
+  ): Promise<{ success: boolean; targets: GotoTarget[]; message?: string }> {
     const session = this.getSession(sessionId);
-    const tid = threadId ?? session.currentThreadId;
+
+    if (!session.client.getCapabilities()?.supportsGotoTargetsRequest) {
+      return {
+        success: false,
+        targets: [],
+        message: `${session.adapter.name} does not support jumping to a line; use run_to_line or restart instead`
+      };
+    }
+    if (session.info.state !== SessionState.PAUSED) {
+      return { success: false, targets: [], message: 'Program must be paused to list goto targets' };
+    }
 
     try {
-      await session.client.stepIn(tid);
-      await this.waitForPause(sessionId);
+      const targets: GotoTarget[] = (await session.client.gotoTargets(path.resolve(file), line)).map((t) => ({
+        id: t.id,
+        label: t.label,
+        line: t.line,
+        column: t.column,
+        endLine: t.endLine
+      }));
+      session.gotoTargets = new Map(targets.map((t) => [t.id, t]));
       return {
         success: true,
-        state: session.info.state,
-        stoppedAt: session.lastStopContext?.stackFrame,
-        variables: session.lastStopContext?.variables
+        targets,
+        message: targets.length === 0 ? `No goto targets at ${file}:${line}` : undefined
       };
     } catch (error) {
       return {
         success: false,
-        state: session.info.state
+        targets: [],
+        message: `Goto targets failed: ${error instanceof Error ? error.message : error}`
       };
     }
   }
 
   /**
-   * Step over
+   * Move the program counter of a paused thread to another line without
+   * running the code in between, to skip a block or re-execute a line.
+   * Uses the first goto target of file:line unless targetId is given.
    */
-  async stepOver(
+  async goto(
     sessionId: string,
-    threadId?: number
-  ): Promise<{ success: boolean; state: SessionState; stoppedAt?: StackFrame; variables?: Variable[] }> {
+    request: { file?: string; line?: number; targetId?: number; threadId?: number }
+  ): Promise<GotoResult> {
     const session = this.getSession(sessionId);
-    const tid = threadId ?? session.currentThreadId;
+    const tid = request.threadId ?? session.currentThreadId;
+
+    let target: GotoTarget | undefined;
+    if (request.targetId === undefined) {
+      if (!request.file || request.line === undefined) {
+        return { success: false, state: session.info.state, message: 'Pass file and line, or a targetId from goto_targets' };
+      }
+      const listed = await this.getGotoTargets(sessionId, request.file, request.line);
+      if (!listed.success || listed.targets.length === 0) {
+        return {
+          success: false,
+          state: session.info.state,
+          message: listed.message ?? `No goto targets at ${request.file}:${request.line}`
+        };
+      }
+      target = listed.targets[0];
+    } else if (!session.client.getCapabilities()?.supportsGotoTargetsRequest) {
+      return {
+        success: false,
+        state: session.info.state,
+        message: `${session.adapter.name} does not support jumping to a line; use run_to_line or restart instead`
+      };
+    } else if (session.info.state !== SessionState.PAUSED) {
+      return {
+        success: false,
+        state: session.info.state,
+        message: `Cannot jump: program is not paused (state: ${session.info.state})`
+      };
+    } else {
+      target = session.gotoTargets?.get(request.targetId);
+    }
 
     try {
-      await session.client.next(tid);
+      // Without a known target line, the jump is confirmed by the frame changing
+      const [before] = await session.client.stackTrace(tid, 0, 1);
+
+      // The adapter answers, then reports the new position with a 'goto' stop
+      this.updateState(sessionId, SessionState.RUNNING);
+      await session.client.goto(tid, target?.id ?? request.targetId!);
       await this.waitForPause(sessionId);
+
+      if (session.info.state === SessionState.ERROR) {
+        return {
+          success: false,
+          state: session.info.state,
+          message: session.info.error ?? 'Debug session failed'
+        };
+      }
+      // Nothing ran, so the thread is still stopped even without a stop event
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
+      }
+
+      // Read the location back to confirm the program counter moved
+      const [top] = await session.client.stackTrace(tid, 0, 1);
+      const expectedLine = target?.line ?? request.line;
+      const moved = top !== undefined && (
+        expectedLine !== undefined
+          ? top.line === expectedLine
+          : before === undefined ||
+            top.line !== before.line ||
+            top.column !== before.column ||
+            top.instructionPointerReference !== before.instructionPointerReference
+      );
       return {
-        success: true,
+        success: moved,
         state: session.info.state,
-        stoppedAt: session.lastStopContext?.stackFrame,
-        variables: session.lastStopContext?.variables
+        target,
+        moved,
+        stoppedAt: top,
+        stopReason: session.lastStop,
+        variables: session.lastStopContext?.variables,
+        message: moved
+          ? `Jumped to ${top.file}:${top.line}`
+          : `Goto was accepted but the program is at ${top ? `${top.file}:${top.line}` : 'an unknown location'}` +
+            (expectedLine !== undefined ? `, not line ${expectedLine}` : '; it did not move')
       };
     } catch (error) {
+      // The jump was rejected, so the program is still where it was
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
+      }
       return {
         success: false,
-        state: session.info.state
+        state: session.info.state,
+        target,
+        moved: false,
+        message: `Goto failed: ${error instanceof Error ? error.message : error}`
       };
     }
   }
 
   /**
-   * Step out
+   * Read raw bytes at a memory reference, e.g. a variable's memoryReference
    */
-  async stepOut(
+  async readMemory(
     sessionId: string,
-    threadId?: number
-  ): Promise<{ success: boolean; state: SessionState; stoppedAt?: StackFrame; variables?: Variable[] }> {
+    request: {
+      memoryReference: string;
+      offset?: number;
+      count?: number;
+      format?: 'hex' | 'base64' | 'both';
+    }
+  ): Promise<MemoryRead> {
+    const session = this.getSession(sessionId);
+    const count = Math.min(request.count ?? 64, MAX_MEMORY_READ_BYTES);
+    const format = request.format ?? 'hex';
+
+    if (!session.client.getCapabilities()?.supportsReadMemoryRequest) {
+      return {
+        success: false,
+        count: 0,
+        message: `${session.adapter.name} does not support reading memory`
+      };
+    }
+    if (session.info.state !== SessionState.PAUSED) {
+      return { success: false, count: 0, message: 'Program must be paused to read memory' };
+    }
+
+    try {
+      const body = await session.client.readMemory(request.memoryReference, request.offset ?? 0, count);
+      const bytes = Buffer.from(body?.data ?? '', 'base64');
+      const truncated = (request.count ?? 0) > MAX_MEMORY_READ_BYTES;
+      return {
+        success: true,
+        address: body?.address,
+        count: bytes.length,
+        unreadableBytes: body?.unreadableBytes || undefined,
+        hex: format !== 'base64' ? [...bytes].map((b) => b.toString(16).padStart(2, '0')).join(' ') : undefined,
+        base64: format !== 'hex' ? bytes.toString('base64') : undefined,
+        message: truncated
+          ? `Read limited to ${MAX_MEMORY_READ_BYTES} bytes; use offset to read further`
+          : undefined
+      };
+    } catch (error) {
+      return {
+        success: false,
+        count: 0,
+        message: `Read memory failed: ${error instanceof Error ? error.message : error}`
+      };
+    }
+  }
+
+  /**
+   * Disassemble instructions around a frame's instruction pointer (or a given
+   * memory reference), with source lines resolved where the adapter knows them
+   */
+  async disassemble(
+    sessionId: string,
+    options?: {
+      memoryReference?: string;
+      frameId?: number;
+      instructionOffset?: number;
+      instructionCount?: number;
+    }
+  ): Promise<{ success: boolean; instructions: Instruction[]; message?: string }> {
+    const session = this.getSession(sessionId);
+    const count = options?.instructionCount ?? 20;
+
+    if (!session.client.getCapabilities()?.supportsDisassembleRequest) {
+      return {
+        success: false,
+        instructions: [],
+        message: `${session.adapter.name} does not support disassembly`
+      };
+    }
+    if (session.info.state !== SessionState.PAUSED) {
+      return { success: false, instructions: [], message: 'Program must be paused to disassemble' };
+    }
+
+    let memoryReference = options?.memoryReference;
+    let currentAddress: string | undefined;
+    if (!memoryReference) {
+      const frame = await this.findFrame(session, await this.resolveFrameId(session, options?.frameId));
+      memoryReference = frame?.instructionPointerReference;
+      currentAddress = memoryReference;
+      if (!memoryReference) {
+        return {
+          success: false,
+          instructions: [],
+          message: 'Frame has no instruction pointer; pass a memoryReference instead'
+        };
+      }
+    }
+
+    // Center the window on the instruction pointer by default
+    const offset = options?.instructionOffset ?? -Math.floor(count / 2);
+
+    try {
+      const raw = await session.client.disassemble(memoryReference, offset, count);
+
+      // Locations may be omitted when they repeat the previous instruction's
+      let file: string | undefined;
+      const instructions = raw.map((ins): Instruction => {
+        file = ins.location?.path ?? file;
+        return {
+          address: ins.address,
+          instruction: ins.instruction,
+          instructionBytes: ins.instructionBytes,
+          symbol: ins.symbol,
+          file,
+          line: ins.line,
+          current: currentAddress !== undefined && ins.address === currentAddress
+        };
+      });
+
+      return { success: true, instructions };
+    } catch (error) {
+      return {
+        success: false,
+        instructions: [],
+        message: `Disassemble failed: ${error instanceof Error ? error.message : error}`
+      };
+    }
+  }
+
+  /**
+   * Perform a single step and wait for the resulting stop
+   */
+  private async step(
+    sessionId: string,
+    stepType: 'in' | 'over' | 'out',
+    threadId?: number,
+    granularity?: DebugProtocol.SteppingGranularity
+  ): Promise<StepResult> {
     const session = this.getSession(sessionId);
     const tid = threadId ?? session.currentThreadId;
 
+    if (session.info.state !== SessionState.PAUSED) {
+      return {
+        success: false,
+        state: session.info.state,
+        message: `Cannot step ${stepType}: program is not paused (state: ${session.info.state})`
+      };
+    }
+
     try {
-      await session.client.stepOut(tid);
-      await this.waitForPause(sessionId);
+      // Not every adapter sends 'continued' for steps, so mark the session as
+      // running ourselves; otherwise waitForPause returns the previous stop
+      this.updateState(sessionId, SessionState.RUNNING);
+
+      switch (stepType) {
+        case 'in':
+          await session.client.stepIn(tid, granularity);
+          break;
+        case 'out':
+          await session.client.stepOut(tid, granularity);
+          break;
+        case 'over':
+        default:
+          await session.client.next(tid, granularity);
+          break;
+      }
+
+      await this.waitForPause(sessionId, STEP_TIMEOUT_MS);
+      if (session.info.state === SessionState.ERROR) {
+        return {
+          success: false,
+          state: session.info.state,
+          message: session.info.error ?? 'Debug session failed'
+        };
+      }
+      if (session.info.state === SessionState.TERMINATED) {
+        return {
+          success: true,
+          state: session.info.state,
+          message: `Program exited with code ${session.info.exitCode ?? 'unknown'} during step ${stepType}`
+        };
+      }
+      if (session.info.state !== SessionState.PAUSED) {
+        return {
+          success: false,
+          state: session.info.state,
+          message: `Step ${stepType} did not stop within ${STEP_TIMEOUT_MS}ms; program is still running (use wait_for_stop to keep waiting or pause to interrupt it)`
+        };
+      }
       return {
         success: true,
         state: session.info.state,
         stoppedAt: session.lastStopContext?.stackFrame,
+        stopReason: session.lastStop,
         variables: session.lastStopContext?.variables
       };
     } catch (error) {
+      // The step was rejected, so the program is still where it was
+      if (session.info.state === SessionState.RUNNING) {
+        this.updateState(sessionId, SessionState.PAUSED);
+      }
       return {
         success: false,
-        state: session.info.state
+        state: session.info.state,
+        message: `Step ${stepType} failed: ${error instanceof Error ? error.message : error}`
       };
     }
   }
@@ -955,7 +3532,8 @@ export class SessionManager extends EventEmitter {
 
         stepsCompleted++;
 
-        // Step
+        // Step (mark running so waitForPause waits for the new stop)
+        this.updateState(sessionId, SessionState.RUNNING);
         switch (stepType) {
           case 'in':
             await session.client.stepIn(session.currentThreadId);
@@ -998,39 +3576,171 @@ export class SessionManager extends EventEmitter {
    * Get stack trace
    */
   async getStackTrace(sessionId: string, threadId?: number): Promise<StackFrame[]> {
+    const page = await this.getStackTracePage(sessionId, threadId);
+    return page.stackFrames;
+  }
+
+  /**
+   * Get a page of the stack trace. Frame IDs are valid for the
+   * inspection tools until the session resumes.
+   */
+  async getStackTracePage(
+    sessionId: string,
+    threadId?: number,
+    startFrame: number = 0,
+    levels?: number
+  ): Promise<StackTracePage> {
     const session = this.getSession(sessionId);
     const tid = threadId ?? session.currentThreadId;
 
-    const frames = await session.client.stackTrace(tid);
+    const page = await session.client.stackTracePage(tid, startFrame, levels);
+
+    // Listing the stack does not move the frame chosen with select_frame;
+    // only adopt the top frame if the stop handler could not set one
+    if (
+      startFrame === 0 &&
+      tid === session.currentThreadId &&
+      session.currentFrameId === 0 &&
+      page.stackFrames.length > 0
+    ) {
+      session.currentFrameId = page.stackFrames[0].id;
+    }
+
+    return page;
+  }
+
+  /**
+   * Choose the frame that inspection tools use when no frameId is given,
+   * by ID, by level (0 = top) or one step up/down from the current one
+   */
+  async selectFrame(
+    sessionId: string,
+    request: { frameId?: number; level?: number; direction?: 'up' | 'down'; threadId?: number }
+  ): Promise<FrameSelectionResult> {
+    const session = this.getSession(sessionId);
+
+    if (session.info.state !== SessionState.PAUSED) {
+      return {
+        success: false,
+        message: `Cannot select a frame: program is not paused (state: ${session.info.state})`
+      };
+    }
+
+    const threadId = request.threadId ?? session.frameSelection?.threadId ?? session.currentThreadId;
+    const frames = await session.client.stackTrace(threadId);
+    const currentLevel = session.frameSelection?.threadId === threadId
+      ? session.frameSelection.level
+      : 0;
+
+    let level: number;
+    if (request.frameId !== undefined) {
+      level = frames.findIndex((f) => f.id === request.frameId);
+    } else if (request.level !== undefined) {
+      level = request.level;
+    } else if (request.direction) {
+      // "up" moves toward the caller, like gdb
+      level = currentLevel + (request.direction === 'up' ? 1 : -1);
+    } else {
+      level = 0;
+    }
 
-    // Update current frame ID
-    if (frames.length > 0) {
-      session.currentFrameId = frames[0].id;
+    const frame = frames[level];
+    if (!frame) {
+      return {
+        success: false,
+        threadId,
+        level: currentLevel,
+        totalFrames: frames.length,
+        message: request.frameId !== undefined
+          ? `Frame ${request.frameId} is not on the stack of thread ${threadId}`
+          : `No frame at level ${level}; thread ${threadId} has ${frames.length} frame(s)`
+      };
     }
 
-    return frames;
+    session.currentFrameId = frame.id;
+    session.frameSelection = { threadId, level };
+
+    return {
+      success: true,
+      frame,
+      threadId,
+      level,
+      totalFrames: frames.length,
+      message: `Selected frame #${level} ${frame.name} at ${frame.file}:${frame.line}`
+    };
   }
 
   /**
    * Get scopes for a frame
    */
-  async getScopes(sessionId: string, frameId?: number): Promise<Scope[]> {
+  async getScopes(sessionId: string, frameId?: number, threadId?: number): Promise<Scope[]> {
     const session = this.getSession(sessionId);
-    const fid = frameId ?? session.currentFrameId;
+    const fid = await this.resolveFrameId(session, frameId, threadId);
 
     return session.client.scopes(fid);
   }
 
+  /**
+   * Pick the frame to inspect: an explicit frame, the top frame of another
+   * thread (goroutine), or the current frame
+   */
+  private async resolveFrameId(
+    session: SessionData,
+    frameId?: number,
+    threadId?: number
+  ): Promise<number> {
+    if (frameId !== undefined) {
+      return frameId;
+    }
+    const selectedThreadId = session.frameSelection?.threadId ?? session.currentThreadId;
+    if (threadId !== undefined && threadId !== selectedThreadId) {
+      const [top] = await session.client.stackTrace(threadId, 0, 1);
+      if (!top) {
+        throw new Error(`Thread ${threadId} has no stack frames`);
+      }
+      return top.id;
+    }
+    return session.currentFrameId;
+  }
+
+  /**
+   * Look up a stack frame by ID, in the selected thread first and then in
+   * the other threads (goroutines)
+   */
+  private async findFrame(session: SessionData, frameId: number): Promise<StackFrame | undefined> {
+    const selectedThreadId = session.frameSelection?.threadId ?? session.currentThreadId;
+    const selected = await session.client.stackTrace(selectedThreadId);
+    const frame = selected.find((f) => f.id === frameId);
+    if (frame) {
+      return frame;
+    }
+
+    const threads = await session.client.threads();
+    for (const thread of threads.slice(0, MAX_THREAD_LOCATIONS)) {
+      if (thread.id === selectedThreadId) {
+        continue;
+      }
+      const frames = await session.client.stackTrace(thread.id).catch((): StackFrame[] => []);
+      const match = frames.find((f) => f.id === frameId);
+      if (match) {
+        return match;
+      }
+    }
+    return undefined;
+  }
+
   /**
    * Get variables
    */
   async getVariables(
     sessionId: string,
     frameId?: number,
-    scopeFilter?: 'local' | 'global' | 'closure'
+    scopeFilter?: 'local' | 'global' | 'closure',
+    depth: number = 0,
+    threadId?: number
   ): Promise<Variable[]> {
     const session = this.getSession(sessionId);
-    const fid = frameId ?? session.currentFrameId;
+    const fid = await this.resolveFrameId(session, frameId, threadId);
 
     // Get scopes for the frame
     const scopes = await session.client.scopes(fid);
@@ -1047,44 +3757,233 @@ export class SessionManager extends EventEmitter {
     const allVariables: Variable[] = [];
     for (const scope of targetScopes) {
       const vars = await session.client.variables(scope.variablesReference);
-      allVariables.push(...vars);
+      allVariables.push(...this.limitVariables(session, vars));
     }
 
-    return allVariables;
+    return this.expandTree(session, allVariables, depth);
   }
 
   /**
-   * Expand a variable (get its children)
+   * Expand a variable (get its children), optionally recursing into
+   * grandchildren up to the given depth
    */
   async expandVariable(
     sessionId: string,
-    variablesReference: number
+    variablesReference: number,
+    depth: number = 1
   ): Promise<Variable[]> {
     const session = this.getSession(sessionId);
-    return session.client.variables(variablesReference);
+    const children = await session.client.variables(variablesReference);
+    return this.expandTree(
+      session,
+      this.limitVariables(session, children),
+      depth - 1,
+      new Set([variablesReference])
+    );
+  }
+
+  /**
+   * Recursively attach children to expandable variables, up to a capped depth.
+   * References already visited on this walk are skipped to break cycles.
+   */
+  private async expandTree(
+    session: SessionData,
+    variables: Variable[],
+    depth: number,
+    visited: Set<number> = new Set()
+  ): Promise<Variable[]> {
+    const remaining = Math.min(depth, MAX_EXPANSION_DEPTH);
+    if (remaining <= 0) {
+      return variables;
+    }
+
+    for (const variable of variables) {
+      if (!variable.hasChildren || visited.has(variable.variablesReference)) {
+        continue;
+      }
+      visited.add(variable.variablesReference);
+
+      const children = await session.client.variables(variable.variablesReference);
+      const total = (variable.indexedVariables ?? 0) + (variable.namedVariables ?? 0);
+      variable.children = await this.expandTree(
+        session,
+        this.limitVariables(session, children, total),
+        remaining - 1,
+        visited
+      );
+    }
+
+    return variables;
+  }
+
+  /**
+   * Apply the session's limits to a list of variables: long values are cut
+   * down, and past the array (indexed children) or map (named children)
+   * limit the rest are replaced by a single "...truncated, N more" entry.
+   * total is the child count the adapter reported, when known.
+   */
+  private limitVariables(session: SessionData, variables: Variable[], total: number = 0): Variable[] {
+    const { maxStringLength, maxArrayElements, maxMapEntries } = session.limits;
+    const indexed = variables.length > 0 && variables.every((v) => INDEXED_CHILD_NAME.test(v.name));
+    const maxEntries = indexed ? maxArrayElements : maxMapEntries;
+
+    const limited = variables.slice(0, maxEntries).map((v) => ({
+      ...v,
+      value: truncateValue(v.value, maxStringLength)
+    }));
+
+    const omitted = Math.max(total, variables.length) - limited.length;
+    if (omitted > 0) {
+      limited.push({
+        name: '...',
+        value: `...truncated, ${omitted} more ${indexed ? 'elements' : 'entries'}`,
+        type: '',
+        variablesReference: 0,
+        hasChildren: false
+      });
+    }
+    return limited;
   }
 
   /**
-   * Evaluate an expression
+   * Evaluate an expression in a stack frame (top frame by default).
+   * Evaluation errors are returned with the adapter's message verbatim.
    */
   async evaluateExpression(
     sessionId: string,
     expression: string,
     frameId?: number,
-    context?: 'watch' | 'repl' | 'hover'
-  ): Promise<EvaluationResult> {
+    context: 'watch' | 'repl' | 'hover' = 'repl',
+    threadId?: number
+  ): Promise<ExpressionEvaluation> {
+    const session = this.getSession(sessionId);
+
+    try {
+      const fid = await this.resolveFrameId(session, frameId, threadId);
+      const result = await session.client.evaluate(expression, fid, context);
+      result.result = truncateValue(result.result, session.limits.maxStringLength);
+      return { success: true, expression, ...result };
+    } catch (error) {
+      return {
+        success: false,
+        expression,
+        error: error instanceof Error ? error.message : String(error)
+      };
+    }
+  }
+
+  /**
+   * Suggest completions for a partial expression in a stack frame (the
+   * selected frame by default), so field and method names can be looked up
+   * before evaluating
+   */
+  async getCompletions(
+    sessionId: string,
+    request: { text: string; column?: number; frameId?: number; threadId?: number }
+  ): Promise<CompletionsResult> {
+    const session = this.getSession(sessionId);
+
+    if (!session.client.getCapabilities()?.supportsCompletionsRequest) {
+      return {
+        success: false,
+        suggestions: [],
+        message: `${session.adapter.name} does not support completions; use get_variables or expand_variable to list fields instead`
+      };
+    }
+
+    try {
+      // Completions are scoped to a frame only while paused
+      const fid = session.info.state === SessionState.PAUSED
+        ? await this.resolveFrameId(session, request.frameId, request.threadId)
+        : undefined;
+      // The cursor defaults to the end of the text
+      const column = request.column ?? request.text.length + 1;
+      const targets = await session.client.completions(request.text, column, fid);
+      return {
+        success: true,
+        suggestions: targets.map((t) => ({
+          label: t.label,
+          text: t.text !== undefined && t.text !== t.label ? t.text : undefined,
+          type: t.type,
+          detail: t.detail,
+          start: t.start,
+          length: t.length
+        }))
+      };
+    } catch (error) {
+      return {
+        success: false,
+        suggestions: [],
+        message: `Completions failed: ${error instanceof Error ? error.message : error}`
+      };
+    }
+  }
+
+  /**
+   * Evaluate an expression and expand its result into a value tree in one
+   * call, bounded by depth and the session's variable limits
+   */
+  async inspectExpression(
+    sessionId: string,
+    expression: string,
+    options: { frameId?: number; threadId?: number; depth?: number } = {}
+  ): Promise<ExpressionInspection> {
     const session = this.getSession(sessionId);
-    const fid = frameId ?? session.currentFrameId;
+    const evaluation = await this.evaluateExpression(
+      sessionId,
+      expression,
+      options.frameId,
+      'watch',
+      options.threadId
+    );
+    if (!evaluation.success || !evaluation.variablesReference) {
+      return evaluation;
+    }
 
-    return session.client.evaluate(expression, fid, context);
+    try {
+      const depth = options.depth ?? 2;
+      const children = await session.client.variables(evaluation.variablesReference);
+      const total = (evaluation.indexedVariables ?? 0) + (evaluation.namedVariables ?? 0);
+      return {
+        ...evaluation,
+        children: await this.expandTree(
+          session,
+          this.limitVariables(session, children, total),
+          depth - 1,
+          new Set([evaluation.variablesReference])
+        )
+      };
+    } catch (error) {
+      return {
+        ...evaluation,
+        error: `Evaluated, but expanding the result failed: ${error instanceof Error ? error.message : error}`
+      };
+    }
   }
 
   /**
    * Get threads
    */
-  async getThreads(sessionId: string): Promise<ThreadInfo[]> {
+  async getThreads(
+    sessionId: string,
+    options?: { withLocation?: boolean }
+  ): Promise<ThreadInfo[]> {
     const session = this.getSession(sessionId);
-    return session.client.threads();
+    const threads = await session.client.threads();
+
+    // Stacks can only be read while paused; a location shows where each
+    // thread (goroutine) is blocked
+    if (options?.withLocation && session.info.state === SessionState.PAUSED) {
+      for (const thread of threads.slice(0, MAX_THREAD_LOCATIONS)) {
+        try {
+          [thread.location] = await session.client.stackTrace(thread.id, 0, 1);
+        } catch {
+          // Thread may have exited since the threads request
+        }
+      }
+    }
+
+    return threads;
   }
 
   /**
@@ -1092,57 +3991,171 @@ export class SessionManager extends EventEmitter {
    */
   async getSourceContext(
     sessionId: string,
-    file?: string,
-    line?: number,
-    linesContext: number = 5
+    request: SourceContextRequest = {}
   ): Promise<SourceContext | null> {
     const session = this.getSession(sessionId);
+    const linesContext = request.linesContext ?? 5;
 
     // Get current location if not specified
-    let targetFile = file;
-    let targetLine = line;
-
-    if (!targetFile || !targetLine) {
-      const frames = await this.getStackTrace(sessionId);
-      if (frames.length === 0) {
+    let targetFile = request.file;
+    let targetLine = request.line;
+    let frame: StackFrame | undefined;
+
+    if (!targetFile || !targetLine || request.frameId !== undefined) {
+      // Without an explicit frame, show the one chosen with select_frame
+      const useSelection = request.threadId === undefined && session.frameSelection !== undefined;
+      const frames = await this.getStackTrace(
+        sessionId,
+        useSelection ? session.frameSelection?.threadId : request.threadId
+      );
+      frame = request.frameId !== undefined
+        ? frames.find((f) => f.id === request.frameId)
+        : (useSelection && frames.find((f) => f.id === session.currentFrameId)) || frames[0];
+      if (!frame) {
+        if (request.frameId !== undefined) {
+          throw new Error(`Frame ${request.frameId} not found. Use get_stack_trace for valid frame IDs.`);
+        }
         return null;
       }
-      targetFile = frames[0].file;
-      targetLine = frames[0].line;
+      targetFile = targetFile ?? frame.file;
+      targetLine = targetLine ?? frame.line;
     }
 
-    // Read the source file
-    try {
-      const content = await fs.readFile(targetFile, 'utf8');
-      const allLines = content.split('\n');
-
-      const startLine = Math.max(1, targetLine - linesContext);
-      const endLine = Math.min(allLines.length, targetLine + linesContext);
-
-      // Get breakpoints for this file
-      const breakpoints = session.breakpoints.get(targetFile) ?? [];
-      const breakpointLines = new Set(breakpoints.map((bp) => bp.line));
-
-      const lines: SourceLine[] = [];
-      for (let i = startLine; i <= endLine; i++) {
-        lines.push({
-          lineNumber: i,
-          content: allLines[i - 1] ?? '',
-          isCurrent: i === targetLine,
-          hasBreakpoint: breakpointLines.has(i)
-        });
-      }
+    const loaded = await this.loadSource(session, targetFile, frame);
+    if (!loaded) {
+      return null;
+    }
+
+    const allLines = loaded.content.split('\n');
+
+    const startLine = Math.max(1, targetLine - linesContext);
+    const endLine = Math.min(allLines.length, targetLine + linesContext);
+
+    // Get breakpoints for this file
+    const breakpoints = session.breakpoints.get(targetFile) ?? [];
+    const breakpointLines = new Set(breakpoints.map((bp) => bp.line));
+
+    const lines: SourceLine[] = [];
+    for (let i = startLine; i <= endLine; i++) {
+      lines.push({
+        lineNumber: i,
+        content: allLines[i - 1] ?? '',
+        isCurrent: i === targetLine,
+        hasBreakpoint: breakpointLines.has(i)
+      });
+    }
+
+    return {
+      file: targetFile,
+      startLine,
+      endLine,
+      currentLine: targetLine,
+      origin: loaded.origin,
+      lines
+    };
+  }
 
+  /**
+   * Bundle the stop reason, top stack frames, top-frame locals and source
+   * around the current line so one call is enough to orient after a stop
+   */
+  async getStopContext(sessionId: string, request: StopContextRequest = {}): Promise<StopContext> {
+    const session = this.getSession(sessionId);
+
+    if (session.info.state !== SessionState.PAUSED) {
       return {
-        file: targetFile,
-        startLine,
-        endLine,
-        currentLine: targetLine,
-        lines
+        success: false,
+        state: session.info.state,
+        message: `Program is not paused (state: ${session.info.state})`
       };
+    }
+
+    const threadId = request.threadId ?? session.currentThreadId;
+    const maxVariables = request.maxVariables ?? 50;
+    const linesContext = request.linesContext ?? 5;
+
+    const page = await this.getStackTracePage(sessionId, threadId, 0, Math.max(1, request.frames ?? 5));
+    const [top] = page.stackFrames;
+    const context: StopContext = {
+      success: true,
+      state: session.info.state,
+      threadId,
+      stopReason: session.lastStop?.threadId === threadId ? session.lastStop : undefined,
+      frames: page.stackFrames,
+      totalFrames: page.totalFrames
+    };
+
+    if (!top) {
+      context.message = `Thread ${threadId} has no stack frames`;
+      return context;
+    }
+
+    if (maxVariables > 0) {
+      const scopes = await session.client.scopes(top.id);
+      const localScope = scopes.find((s) => s.name.toLowerCase().includes('local')) ?? scopes[0];
+      if (localScope) {
+        const locals = await session.client.variables(localScope.variablesReference);
+        context.locals = await this.expandTree(
+          session,
+          this.limitVariables(session, locals.slice(0, maxVariables)),
+          request.depth ?? 0
+        );
+        if (locals.length > maxVariables) {
+          context.omittedLocals = locals.length - maxVariables;
+        }
+      }
+    }
+
+    if (linesContext >= 0) {
+      context.source = (await this.getSourceContext(sessionId, {
+        frameId: top.id,
+        threadId,
+        linesContext
+      })) ?? undefined;
+    }
+
+    return context;
+  }
+
+  /**
+   * Read source text, preferring the adapter's copy when the frame has one
+   * and falling back to it when the file is not on this machine
+   */
+  private async loadSource(
+    session: SessionData,
+    file: string,
+    frame?: StackFrame
+  ): Promise<{ content: string; origin: 'disk' | 'adapter' } | null> {
+    const fromFrame = frame && frame.file === file ? frame : undefined;
+
+    if (fromFrame?.sourceReference) {
+      try {
+        const content = await session.client.source({
+          path: fromFrame.file,
+          sourceReference: fromFrame.sourceReference
+        });
+        return { content, origin: 'adapter' };
+      } catch {
+        // Fall back to the file on disk
+      }
+    }
+
+    try {
+      return { content: await fs.readFile(file, 'utf8'), origin: 'disk' };
     } catch {
-      return null;
+      // The file may only exist where the debuggee runs (remote debugging)
     }
+
+    if (fromFrame) {
+      try {
+        const content = await session.client.source({ path: file });
+        return { content, origin: 'adapter' };
+      } catch {
+        // Adapter cannot serve it either
+      }
+    }
+
+    return null;
   }
 
   /**
@@ -1150,16 +4163,36 @@ export class SessionManager extends EventEmitter {
    */
   async terminateSession(
     sessionId: string
-  ): Promise<{ success: boolean; message: string }> {
+  ): Promise<{ success: boolean; message: string; termination?: TerminationInfo }> {
     const session = this.sessions.get(sessionId);
     if (!session) {
+      // The adapter is already gone; just forget the record
+      const finished = this.finishedSessions.get(sessionId);
+      if (finished) {
+        this.finishedSessions.delete(sessionId);
+        return {
+          success: true,
+          message: `Session terminated (${this.describeFinished(sessionId, finished.session)})`,
+          termination: finished.session.info.termination
+        };
+      }
       return { success: false, message: 'Session not found' };
     }
 
     try {
-      await session.client.disconnect(true);
+      // A program that already ended keeps its own termination reason
+      if (session.info.state !== SessionState.TERMINATED) {
+        session.terminationRequested = true;
+      }
+
+      // Attached programs keep running; only launched ones are killed
+      await session.client.disconnect(session.info.mode !== 'attach');
       this.sessions.delete(sessionId);
-      return { success: true, message: 'Session terminated' };
+      return {
+        success: true,
+        message: 'Session terminated',
+        termination: session.info.termination ?? this.getTermination(session, 'debugger')
+      };
     } catch (error) {
       // Force cleanup even if disconnect fails
       this.sessions.delete(sessionId);
@@ -1177,11 +4210,93 @@ export class SessionManager extends EventEmitter {
     return this.getSession(sessionId).info;
   }
 
+  /**
+   * Get the capabilities the session's adapter reported during initialize
+   */
+  getCapabilities(sessionId: string): {
+    adapter: string;
+    initialized: boolean;
+    capabilities?: AdapterCapabilities;
+    exceptionBreakpointFilters?: DebugProtocol.ExceptionBreakpointsFilter[];
+    message?: string;
+  } {
+    const session = this.getSession(sessionId);
+    const capabilities = session.client.getCapabilities();
+
+    if (!capabilities) {
+      return {
+        adapter: session.adapter.name,
+        initialized: false,
+        message: 'Capabilities are reported by the adapter once debugging starts'
+      };
+    }
+
+    return {
+      adapter: session.adapter.name,
+      initialized: true,
+      capabilities: convertCapabilities(capabilities) as AdapterCapabilities,
+      exceptionBreakpointFilters: capabilities.exceptionBreakpointFilters ?? []
+    };
+  }
+
+  /**
+   * Get the run state of a session, including the exit status once the program ended
+   */
+  getStatus(sessionId: string): {
+    sessionId: string;
+    state: SessionState;
+    lifecycle: SessionLifecycle;
+    /** Whether the adapter handshake is done and commands take effect immediately */
+    ready: boolean;
+    mode?: SessionMode;
+    exitCode?: number;
+    termination?: TerminationInfo;
+    stoppedAt?: StackFrame;
+    stopReason?: StopInfo;
+    error?: string;
+  } {
+    const session = this.getSessionOrFinished(sessionId);
+    const paused = session.info.state === SessionState.PAUSED;
+
+    const state = session.info.state;
+
+    return {
+      sessionId,
+      state,
+      lifecycle: LIFECYCLE_BY_STATE[state],
+      ready: state === SessionState.READY || state === SessionState.RUNNING || state === SessionState.PAUSED,
+      mode: session.info.mode,
+      exitCode: session.info.exitCode,
+      termination: session.info.termination,
+      stoppedAt: paused ? session.lastStopContext?.stackFrame : undefined,
+      stopReason: paused ? session.lastStop : undefined,
+      error: session.info.error
+    };
+  }
+
   /**
    * List all sessions
    */
-  listSessions(): DebugSessionInfo[] {
-    return Array.from(this.sessions.values()).map((s) => s.info);
+  listSessions(): SessionSummary[] {
+    const now = Date.now();
+    return Array.from(this.sessions.values()).map((s) => ({
+      ...s.info,
+      idleMs: now - s.lastActivity
+    }));
+  }
+
+  /**
+   * Concurrent session cap (0 means unlimited)
+   */
+  getMaxSessions(): number {
+    return this.maxSessions;
+  }
+
+  /**
+   * Get a session, or the record of one whose program has ended
+   */
+  private getSessionOrFinished(sessionId: string): SessionData {
+    return this.finishedSessions.get(sessionId)?.session ?? this.getSession(sessionId);
   }
 
   /**
@@ -1190,19 +4305,72 @@ export class SessionManager extends EventEmitter {
   private getSession(sessionId: string): SessionData {
     const session = this.sessions.get(sessionId);
     if (!session) {
+      const finished = this.finishedSessions.get(sessionId);
+      if (finished) {
+        throw new Error(
+          `${this.describeFinished(sessionId, finished.session)}. get_status and get_output still work; ` +
+          'use restart to run it again or create a new session.'
+        );
+      }
+      if (this.expiredSessions.has(sessionId)) {
+        throw new Error(
+          `Session expired: ${sessionId} was terminated after ${Math.round(this.idleTimeoutMs / 1000)}s of inactivity`
+        );
+      }
       throw new Error(`Session not found: ${sessionId}`);
     }
+    // Every tool call goes through here, so this doubles as activity tracking
+    session.lastActivity = Date.now();
     return session;
   }
 
+  /**
+   * Terminate every session at once, reporting how each one ended
+   */
+  async terminateAll(): Promise<{
+    success: boolean;
+    terminated: Array<{ sessionId: string; name: string; termination?: TerminationInfo }>;
+    message: string;
+  }> {
+    const sessions = Array.from(this.sessions.values()).map((s) => s.info);
+    const results = await Promise.all(
+      sessions.map((info) => this.terminateSession(info.id).catch(() => undefined))
+    );
+
+    return {
+      success: true,
+      terminated: sessions.map((info, i) => ({
+        sessionId: info.id,
+        name: info.name,
+        termination: results[i]?.termination
+      })),
+      message: sessions.length === 0
+        ? 'No active sessions'
+        : `Terminated ${sessions.length} session(s)`
+    };
+  }
+
+  /**
+   * SIGKILL every session's adapter and debuggee without waiting. Last resort
+   * for a process 'exit' handler, where nothing asynchronous can run.
+   */
+  killAll(): void {
+    for (const session of this.sessions.values()) {
+      session.client.forceKill();
+    }
+    this.sessions.clear();
+  }
+
   /**
    * Clean up all sessions
    */
   async shutdown(): Promise<void> {
-    const sessionIds = Array.from(this.sessions.keys());
-    for (const sessionId of sessionIds) {
-      await this.terminateSession(sessionId).catch(() => {});
+    if (this.idleSweepTimer) {
+      clearInterval(this.idleSweepTimer);
+      this.idleSweepTimer = null;
     }
+
+    await this.terminateAll();
   }
 }
 
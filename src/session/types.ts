@@ -51,6 +51,78 @@ export type StopReason =
   | 'data breakpoint'
   | 'instruction breakpoint';
 
+/**
+ * A breakpoint that caused a stop
+ */
+export interface HitBreakpoint {
+  id: number;
+  kind: 'line' | 'function' | 'data';
+  /** "file:line" for line breakpoints, otherwise the function or variable name */
+  location: string;
+}
+
+/**
+ * A saved breakpoint the adapter could not bind when it was replayed
+ */
+export interface UnboundBreakpoint {
+  kind: 'line' | 'function';
+  /** "file:line" for line breakpoints, otherwise the function name */
+  location: string;
+  message?: string;
+}
+
+/**
+ * Why the program stopped, from the DAP 'stopped' event
+ */
+export interface StopInfo {
+  reason: StopReason;
+  threadId: number;
+  /** Human-readable description (e.g. "Paused on exception") */
+  description?: string;
+  /** Additional detail, such as the exception or panic message */
+  text?: string;
+  /** Whether all threads were stopped */
+  allThreadsStopped?: boolean;
+  /** Breakpoints reported as hit by the adapter */
+  hitBreakpoints?: HitBreakpoint[];
+  /** Exception details, for stops with reason 'exception' */
+  exception?: ExceptionDetails;
+}
+
+/**
+ * Details of an exception or panic, from the DAP 'exceptionInfo' request
+ */
+export interface ExceptionDetails {
+  exceptionId: string;
+  description?: string;
+  breakMode: DebugProtocol.ExceptionBreakMode;
+  /** Exception type and stack trace as reported by the adapter */
+  typeName?: string;
+  stackTrace?: string;
+}
+
+/**
+ * How the debuggee ended: exited on its own, killed by a signal, stopped by
+ * a tool (terminate_session), or lost when the debug adapter exited
+ */
+export type TerminationReason = 'exited' | 'signal' | 'debugger' | 'adapter-exit';
+
+/**
+ * How and with what code the debuggee terminated
+ */
+export interface TerminationInfo {
+  reason: TerminationReason;
+  /** Exit code from the DAP 'exited' event, if the adapter reported one */
+  exitCode?: number;
+  /** Signal number, when the exit code indicates death by signal (128 + N) */
+  signal?: number;
+}
+
+/**
+ * How the session obtained its debuggee
+ */
+export type SessionMode = 'launch' | 'attach';
+
 /**
  * Information about a debug session
  */
@@ -61,10 +133,15 @@ export interface DebugSessionInfo {
   state: SessionState;
   scriptPath?: string;
   workingDirectory?: string;
+  /** Whether the debuggee was launched by us or attached to */
+  mode?: SessionMode;
+  /** Process ID of the debuggee when attached */
+  processId?: number;
   createdAt: Date;
   stoppedReason?: StopReason;
   stoppedThreadId?: number;
   exitCode?: number;
+  termination?: TerminationInfo;
   error?: string;
 }
 
@@ -72,9 +149,30 @@ export interface DebugSessionInfo {
  * Parameters for creating a new debug session
  */
 export interface SessionCreateParams {
-  language: DebugLanguage;
+  /** Language to debug (detected from scriptPath when omitted) */
+  language?: DebugLanguage;
+  /** Entry point, used to detect the language by file extension */
+  scriptPath?: string;
   name?: string;
   executablePath?: string;
+  /** Record DAP traffic to stderr (or MCP_DEBUGGER_TRACE); false turns the variable off */
+  trace?: boolean;
+  /** Record DAP traffic to this file, as JSON lines */
+  traceFile?: string;
+  /** Caps applied to variables and evaluation results */
+  limits?: VariableLimits;
+}
+
+/**
+ * Caps on how much of a variable an inspection returns
+ */
+export interface VariableLimits {
+  /** Characters of a value kept before it is cut off (default 1000) */
+  maxStringLength?: number;
+  /** Elements of an array, slice or list returned (default 100) */
+  maxArrayElements?: number;
+  /** Map entries, or other named children such as struct fields, returned (default 100) */
+  maxMapEntries?: number;
 }
 
 /**
@@ -86,6 +184,30 @@ export interface LaunchParams {
   cwd?: string;
   env?: Record<string, string>;
   stopOnEntry?: boolean;
+  /** Host of an already-running DAP server to launch through (default: 127.0.0.1) */
+  host?: string;
+  /** Port of an already-running DAP server; when set no local adapter is spawned */
+  port?: number;
+  /** Compile the target first and report diagnostics instead of launching a broken build (default: true) */
+  validateBuild?: boolean;
+  /** Run the package's tests instead of its main program (implied by a _test.go scriptPath or testFilter) */
+  test?: boolean;
+  /** Only run tests matching this regular expression, like go test -run */
+  testFilter?: string;
+}
+
+/**
+ * Parameters for attaching to an already-running program
+ */
+export interface AttachParams {
+  /** Process ID of a local program to attach to */
+  processId?: number;
+  /** Host of a running debug server (default: 127.0.0.1) */
+  host?: string;
+  /** Port of a running debug server */
+  port?: number;
+  /** Working directory used to resolve relative source paths */
+  cwd?: string;
 }
 
 /**
@@ -96,8 +218,10 @@ export interface BreakpointInfo {
   id: number;
   /** Source file path */
   file: string;
-  /** Line number (1-based) */
+  /** Line number (1-based); the adapter may move it to the nearest executable line */
   line: number;
+  /** Line originally requested (differs from line when the adapter moved it) */
+  requestedLine?: number;
   /** Column number (1-based, optional) */
   column?: number;
   /** Whether the breakpoint has been verified by the adapter */
@@ -137,6 +261,135 @@ export interface SetBreakpointRequest {
   maxDumps?: number;
 }
 
+/**
+ * Identifies a breakpoint to remove, either by file+line or by adapter ID
+ */
+export interface RemoveBreakpointRequest {
+  file?: string;
+  line?: number;
+  breakpointId?: number;
+}
+
+/**
+ * Result of removing or clearing breakpoints
+ */
+export interface BreakpointChangeResult {
+  success: boolean;
+  message: string;
+  /** Breakpoints remaining after the change */
+  breakpoints: BreakpointInfo[];
+}
+
+/**
+ * Outcome of one entry of a batch breakpoint request
+ */
+export interface BreakpointSetResult {
+  file: string;
+  /** Line as requested; breakpoint.line is where the adapter bound it */
+  line: number;
+  success: boolean;
+  breakpoint?: BreakpointInfo;
+  /** The adapter cannot honour the condition, hit condition or log message, so it stops unconditionally */
+  conditionIgnored?: boolean;
+  message?: string;
+}
+
+/**
+ * Information about a function breakpoint
+ */
+export interface FunctionBreakpointInfo {
+  /** Unique ID assigned by the debug adapter */
+  id: number;
+  /** Function name as requested (e.g., 'calculate' or 'main.calculate') */
+  name: string;
+  /** Conditional expression (optional) */
+  condition?: string;
+  /** Hit count condition (optional) */
+  hitCondition?: string;
+  /** Whether the adapter resolved the function */
+  verified: boolean;
+  /** Source file the adapter bound the breakpoint to */
+  file?: string;
+  /** Line the adapter bound the breakpoint to */
+  line?: number;
+  /** Additional message from adapter */
+  message?: string;
+}
+
+/**
+ * Request to set a function breakpoint
+ */
+export interface SetFunctionBreakpointRequest {
+  name: string;
+  condition?: string;
+  hitCondition?: string;
+}
+
+/**
+ * Information about a data breakpoint (watchpoint)
+ */
+export interface DataBreakpointInfo {
+  /** Unique ID assigned by the debug adapter */
+  id: number;
+  /** Adapter-specific identifier for the watched data */
+  dataId: string;
+  /** Variable name or expression being watched */
+  name: string;
+  /** Adapter description of the watched data */
+  description: string;
+  /** Kind of access that triggers the breakpoint */
+  accessType: DebugProtocol.DataBreakpointAccessType;
+  /** Conditional expression (optional) */
+  condition?: string;
+  /** Hit count condition (optional) */
+  hitCondition?: string;
+  /** Whether the adapter armed the watchpoint */
+  verified: boolean;
+  /** Additional message from adapter */
+  message?: string;
+}
+
+/**
+ * Request to set a watchpoint
+ */
+export interface SetWatchpointRequest {
+  /** Variable name (or expression, where the adapter supports it) */
+  name: string;
+  /** Access that triggers the stop (default: write) */
+  accessType?: DebugProtocol.DataBreakpointAccessType;
+  /** Frame to resolve the variable in (defaults to current frame) */
+  frameId?: number;
+  condition?: string;
+  hitCondition?: string;
+}
+
+/**
+ * Request to assign a new value to a variable
+ */
+export interface SetVariableRequest {
+  /** Variable name, or an assignable expression where the adapter supports setExpression */
+  name: string;
+  /** New value, parsed by the adapter in the debuggee's language */
+  value: string;
+  /** Frame to resolve the variable in (defaults to current frame) */
+  frameId?: number;
+  /** Container holding the variable, e.g. a struct's reference from get_variables */
+  variablesReference?: number;
+}
+
+/**
+ * Result of assigning a variable
+ */
+export interface SetVariableResult {
+  success: boolean;
+  name: string;
+  /** Value as the adapter parsed and stored it */
+  value?: string;
+  type?: string;
+  variablesReference?: number;
+  message?: string;
+}
+
 /**
  * Stack frame information
  */
@@ -155,6 +408,32 @@ export interface StackFrame {
   moduleId?: number;
   /** Additional presentation hint */
   presentationHint?: 'normal' | 'label' | 'subtle';
+  /** Memory reference of the current instruction, for disassembly */
+  instructionPointerReference?: string;
+  /** Handle for fetching source the adapter holds in memory (e.g. eval'd or remote code) */
+  sourceReference?: number;
+}
+
+/**
+ * Frame chosen with select_frame
+ */
+export interface FrameSelectionResult {
+  success: boolean;
+  frame?: StackFrame;
+  threadId?: number;
+  /** Position on the stack, 0 being the innermost frame */
+  level?: number;
+  totalFrames?: number;
+  message: string;
+}
+
+/**
+ * A page of stack frames
+ */
+export interface StackTracePage {
+  stackFrames: StackFrame[];
+  /** Total frames in the stack, if the adapter reports it */
+  totalFrames?: number;
 }
 
 /**
@@ -179,6 +458,8 @@ export interface Variable {
   memoryReference?: string;
   /** Evaluation name for hover/watch (optional) */
   evaluateName?: string;
+  /** Expanded children, present when the variable was expanded to a depth */
+  children?: Variable[];
 }
 
 /**
@@ -187,6 +468,8 @@ export interface Variable {
 export interface Scope {
   /** Scope name (e.g., 'Locals', 'Globals') */
   name: string;
+  /** Kind of scope, if the adapter reports it (e.g., 'arguments', 'locals', 'registers') */
+  presentationHint?: string;
   /** Reference to variables in this scope */
   variablesReference: number;
   /** Number of named variables */
@@ -202,12 +485,82 @@ export interface Scope {
   };
 }
 
+/**
+ * A disassembled machine instruction with its resolved source location
+ */
+export interface Instruction {
+  address: string;
+  instruction: string;
+  instructionBytes?: string;
+  /** Function or label containing the instruction */
+  symbol?: string;
+  file?: string;
+  line?: number;
+  /** Whether this is the instruction the frame is stopped at */
+  current: boolean;
+}
+
+/**
+ * A completion suggested by the adapter for a partial expression
+ */
+export interface CompletionSuggestion {
+  label: string;
+  /** Text to insert, when it differs from the label */
+  text?: string;
+  /** Kind of item, e.g. 'field', 'method', 'variable' */
+  type?: string;
+  detail?: string;
+  /** 0-based start of the text the suggestion replaces, if not the word before the cursor */
+  start?: number;
+  /** Number of characters the suggestion replaces */
+  length?: number;
+}
+
+/**
+ * Completions for a partial expression
+ */
+export interface CompletionsResult {
+  success: boolean;
+  suggestions: CompletionSuggestion[];
+  message?: string;
+}
+
+/**
+ * A location execution can jump to with goto
+ */
+export interface GotoTarget {
+  id: number;
+  label: string;
+  line: number;
+  column?: number;
+  endLine?: number;
+}
+
+/**
+ * Raw bytes read from the debuggee's memory
+ */
+export interface MemoryRead {
+  success: boolean;
+  /** Address of the first byte returned */
+  address?: string;
+  /** Number of bytes returned */
+  count: number;
+  /** Bytes after the returned ones that could not be read */
+  unreadableBytes?: number;
+  /** Space-separated hex bytes */
+  hex?: string;
+  base64?: string;
+  message?: string;
+}
+
 /**
  * Thread information
  */
 export interface ThreadInfo {
   id: number;
   name: string;
+  /** Top stack frame of the thread, when requested while paused */
+  location?: StackFrame;
 }
 
 /**
@@ -223,6 +576,23 @@ export interface EvaluationResult {
   memoryReference?: string;
 }
 
+/**
+ * Outcome of evaluating an expression; failures carry the adapter's error
+ */
+export interface ExpressionEvaluation extends Partial<EvaluationResult> {
+  success: boolean;
+  expression: string;
+  error?: string;
+}
+
+/**
+ * An evaluated expression with its children expanded into a tree
+ */
+export interface ExpressionInspection extends ExpressionEvaluation {
+  /** Children of the result, each expanded to the requested depth */
+  children?: Variable[];
+}
+
 /**
  * Source context around current execution point
  */
@@ -231,9 +601,61 @@ export interface SourceContext {
   startLine: number;
   endLine: number;
   currentLine: number;
+  /** Where the text came from: the local file or the debug adapter */
+  origin: 'disk' | 'adapter';
   lines: SourceLine[];
 }
 
+/**
+ * Request for source around a frame or a file location
+ */
+export interface SourceContextRequest {
+  /** Source file path; defaults to the frame's file */
+  file?: string;
+  /** Center line; defaults to the frame's line */
+  line?: number;
+  /** Frame to show; defaults to the current frame */
+  frameId?: number;
+  /** Thread whose stack frameId belongs to; defaults to the current thread */
+  threadId?: number;
+  /** Lines of context above and below */
+  linesContext?: number;
+}
+
+/**
+ * How much of each section to include in a stop context
+ */
+export interface StopContextRequest {
+  /** Thread to describe; defaults to the thread that stopped */
+  threadId?: number;
+  /** Number of stack frames from the top (default 5) */
+  frames?: number;
+  /** Maximum top-frame locals to include; 0 omits them (default 50) */
+  maxVariables?: number;
+  /** Levels of nested children to expand for each local (default 0) */
+  depth?: number;
+  /** Lines of source above and below the current line; negative omits source (default 5) */
+  linesContext?: number;
+}
+
+/**
+ * Everything needed to orient after a stop, in one response
+ */
+export interface StopContext {
+  success: boolean;
+  state: SessionState;
+  message?: string;
+  threadId?: number;
+  stopReason?: StopInfo;
+  frames?: StackFrame[];
+  /** Total frames on the stack, if the adapter reports it */
+  totalFrames?: number;
+  locals?: Variable[];
+  /** Number of locals left out by maxVariables */
+  omittedLocals?: number;
+  source?: SourceContext;
+}
+
 /**
  * A line of source code with metadata
  */
@@ -255,6 +677,16 @@ export interface DebugOutput {
   column?: number;
 }
 
+/**
+ * Output captured in a session's output buffer
+ */
+export interface BufferedOutput extends DebugOutput {
+  /** Monotonic sequence number within the session */
+  seq: number;
+  /** Time the output was received (ms since epoch) */
+  timestamp: number;
+}
+
 /**
  * Events emitted by debug sessions
  */
@@ -382,7 +814,9 @@ export function convertStackFrame(frame: DebugProtocol.StackFrame): StackFrame {
     line: frame.line,
     column: frame.column,
     moduleId: frame.moduleId as number | undefined,
-    presentationHint: frame.presentationHint
+    presentationHint: frame.presentationHint,
+    instructionPointerReference: frame.instructionPointerReference,
+    sourceReference: frame.source?.sourceReference || undefined
   };
 }
 
@@ -403,12 +837,13 @@ export function convertVariable(variable: DebugProtocol.Variable): Variable {
 export function convertScope(scope: DebugProtocol.Scope): Scope {
   return {
     name: scope.name,
+    presentationHint: scope.presentationHint,
     variablesReference: scope.variablesReference,
     namedVariables: scope.namedVariables,
     indexedVariables: scope.indexedVariables,
     expensive: scope.expensive ?? false,
     source: scope.source?.path
-      ? { path: scope.source.path, line: scope.source.sourceReference }
+      ? { path: scope.source.path, line: scope.line }
       : undefined
   };
 }
@@ -0,0 +1,91 @@
+/**
+ * Tests for parsing `cargo build --message-format=json` output and picking
+ * the binary to debug
+ */
+
+import { describe, it, expect } from 'vitest';
+import * as fs from 'fs';
+import * as path from 'path';
+import { fileURLToPath } from 'url';
+import {
+  parseCargoBuildOutput,
+  selectCargoExecutable
+} from '../src/adapters/rust/rust-adapter.js';
+
+// Captured from a crate at /work/demo with src/lib.rs (declaring `mod shapes`),
+// src/main.rs and src/bin/tool.rs
+const FIXTURES = path.join(path.dirname(fileURLToPath(import.meta.url)), 'fixtures', 'build_output');
+const MANIFEST_DIR = '/work/demo';
+
+function fixture(name: string): string {
+  return fs.readFileSync(path.join(FIXTURES, name), 'utf8');
+}
+
+describe('parseCargoBuildOutput', () => {
+  it('collects the executables of a successful build', () => {
+    const { diagnostics, executables } = parseCargoBuildOutput(fixture('cargo-bins.jsonl'), MANIFEST_DIR);
+
+    expect(diagnostics).toEqual([]);
+    // The library artifact has no executable and is skipped
+    expect(executables).toEqual([
+      { name: 'demo', srcPath: '/work/demo/src/main.rs', path: '/work/demo/target/debug/demo' },
+      { name: 'tool', srcPath: '/work/demo/src/bin/tool.rs', path: '/work/demo/target/debug/tool' }
+    ]);
+  });
+
+  it('reports errors at their primary span', () => {
+    const { diagnostics, executables } = parseCargoBuildOutput(fixture('cargo-error.jsonl'), MANIFEST_DIR);
+
+    // The trailing "aborting due to 1 previous error" note has no spans
+    expect(diagnostics).toEqual([
+      {
+        file: path.join(MANIFEST_DIR, 'src/main.rs'),
+        line: 3,
+        column: 18,
+        message: 'mismatched types'
+      }
+    ]);
+    expect(executables).toEqual([]);
+  });
+
+  it('reports errors in library modules', () => {
+    const { diagnostics } = parseCargoBuildOutput(fixture('cargo-error-lib.jsonl'), MANIFEST_DIR);
+
+    expect(diagnostics).toHaveLength(1);
+    expect(diagnostics[0].file).toBe(path.join(MANIFEST_DIR, 'src/shapes.rs'));
+    expect(diagnostics[0].line).toBe(1);
+    expect(diagnostics[0].message).toBe('cannot find value `m` in this scope');
+  });
+
+  it('ignores lines that are not JSON messages', () => {
+    const output = ['   Compiling demo v0.1.0 (/work/demo)', '{not json', fixture('cargo-bins.jsonl')].join('\n');
+
+    expect(parseCargoBuildOutput(output, MANIFEST_DIR).executables).toHaveLength(2);
+  });
+});
+
+describe('selectCargoExecutable', () => {
+  const { executables } = parseCargoBuildOutput(fixture('cargo-bins.jsonl'), MANIFEST_DIR);
+
+  it('picks the binary built from the given source file', () => {
+    expect(selectCargoExecutable(executables, '/work/demo/src/bin/tool.rs')?.name).toBe('tool');
+    expect(selectCargoExecutable(executables, '/work/demo/src/main.rs')?.name).toBe('demo');
+  });
+
+  it('does not guess between several binaries', () => {
+    expect(selectCargoExecutable(executables)).toBeUndefined();
+    // A library module is not the crate root of any binary
+    expect(selectCargoExecutable(executables, '/work/demo/src/shapes.rs')).toBeUndefined();
+  });
+
+  it('falls back to the only binary', () => {
+    const [main] = executables;
+
+    expect(selectCargoExecutable([main])).toBe(main);
+    expect(selectCargoExecutable([main], '/work/demo/src/shapes.rs')).toBe(main);
+  });
+
+  it('returns nothing for a library-only build', () => {
+    expect(selectCargoExecutable([])).toBeUndefined();
+  });
+});
@@ -0,0 +1,101 @@
+/**
+ * Tests for parsing `go build` / `go test -c` output into diagnostics
+ */
+
+import { describe, it, expect } from 'vitest';
+import * as path from 'path';
+import { parseGoBuildOutput } from '../src/adapters/go/go-adapter.js';
+
+const CWD = '/work/gb';
+
+describe('parseGoBuildOutput', () => {
+  it('parses file, line, column and message for each error', () => {
+    // go build -o /dev/null . with a type error and an unused variable
+    const output = [
+      '# example.com/gb',
+      './main.go:8:6: declared and not used: s',
+      './main.go:8:17: cannot use 5 (untyped int constant) as string value in variable declaration'
+    ].join('\n');
+
+    expect(parseGoBuildOutput(output, CWD)).toEqual([
+      {
+        file: path.join(CWD, 'main.go'),
+        line: 8,
+        column: 6,
+        message: 'declared and not used: s'
+      },
+      {
+        file: path.join(CWD, 'main.go'),
+        line: 8,
+        column: 17,
+        message: 'cannot use 5 (untyped int constant) as string value in variable declaration'
+      }
+    ]);
+  });
+
+  it('appends tab-indented continuation lines to the previous error', () => {
+    const output = [
+      '# example.com/gb',
+      './main.go:9:2: not enough arguments in call to f',
+      '\thave ()',
+      '\twant (int)'
+    ].join('\n');
+
+    const [diagnostic] = parseGoBuildOutput(output, CWD);
+    expect(diagnostic.line).toBe(9);
+    expect(diagnostic.message).toBe('not enough arguments in call to f\nhave ()\nwant (int)');
+  });
+
+  it('skips package header lines and resolves errors in other packages', () => {
+    // A broken dependency is reported relative to the module root
+    const output = [
+      '# example.com/gb/util',
+      'util/util.go:4:2: undefined: undefinedThing'
+    ].join('\n');
+
+    expect(parseGoBuildOutput(output, CWD)).toEqual([
+      {
+        file: path.join(CWD, 'util/util.go'),
+        line: 4,
+        column: 2,
+        message: 'undefined: undefinedThing'
+      }
+    ]);
+  });
+
+  it('parses test binary builds', () => {
+    // go test -c -o /dev/null . labels the package with its test variant
+    const output = [
+      '# gt [gt.test]',
+      './a_test.go:3:74: cannot use "s" (untyped string constant) as int value in variable declaration'
+    ].join('\n');
+
+    const diagnostics = parseGoBuildOutput(output, CWD);
+    expect(diagnostics).toHaveLength(1);
+    expect(diagnostics[0].file).toBe(path.join(CWD, 'a_test.go'));
+    expect(diagnostics[0].column).toBe(74);
+  });
+
+  it('parses syntax errors from building a single file', () => {
+    // go build -o /dev/null /work/gb/main.go
+    const output = [
+      '# command-line-arguments',
+      './main.go:4:1: syntax error: unexpected }, expected expression'
+    ].join('\n');
+
+    expect(parseGoBuildOutput(output, CWD)).toEqual([
+      {
+        file: path.join(CWD, 'main.go'),
+        line: 4,
+        column: 1,
+        message: 'syntax error: unexpected }, expected expression'
+      }
+    ]);
+  });
+
+  it('ignores output without file positions', () => {
+    const output = "go: go.mod file not found in current directory or any parent directory; see 'go help modules'";
+
+    expect(parseGoBuildOutput(output, CWD)).toEqual([]);
+  });
+});
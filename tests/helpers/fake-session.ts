@@ -0,0 +1,82 @@
+/**
+ * Register a session backed by a fake DAP client on a SessionManager, so
+ * session logic can be tested without starting a debug adapter
+ */
+
+import { EventEmitter } from 'events';
+import { SessionManager } from '../../src/session/session-manager.js';
+import { DebugLanguage, SessionState, Variable } from '../../src/session/types.js';
+
+/**
+ * Stands in for DapClient: serves variables from a fixed tree and records
+ * the references that were requested
+ */
+export class FakeClient extends EventEmitter {
+  readonly tree = new Map<number, Variable[]>();
+  readonly requested: number[] = [];
+  disconnected = false;
+
+  async variables(variablesReference: number): Promise<Variable[]> {
+    this.requested.push(variablesReference);
+    return (this.tree.get(variablesReference) ?? []).map((v) => ({ ...v }));
+  }
+
+  async disconnect(): Promise<void> {
+    this.disconnected = true;
+  }
+}
+
+export interface FakeSession {
+  id: string;
+  client: FakeClient;
+}
+
+let nextId = 1;
+
+/**
+ * Add a session in the given state, wired to the manager's event handlers
+ */
+export function addFakeSession(
+  manager: SessionManager,
+  state: SessionState = SessionState.PAUSED,
+  overrides: Record<string, unknown> = {}
+): FakeSession {
+  const id = `fake-${nextId++}`;
+  const client = new FakeClient();
+  const internals = manager as unknown as {
+    sessions: Map<string, unknown>;
+    setupEventHandlers(sessionId: string, client: FakeClient): void;
+  };
+
+  internals.sessions.set(id, {
+    info: { id, name: id, language: DebugLanguage.GO, state, createdAt: new Date() },
+    client,
+    executablePath: 'dlv',
+    breakpoints: new Map(),
+    functionBreakpoints: [],
+    dataBreakpoints: [],
+    exceptionFilters: [],
+    currentThreadId: 1,
+    currentFrameId: 0,
+    collectedTraces: [],
+    dumpBreakpoints: new Map(),
+    breakpointUpdates: Promise.resolve(),
+    limits: { maxStringLength: 1000, maxArrayElements: 100, maxMapEntries: 100 },
+    outputBuffer: [],
+    outputSeq: 0,
+    outputReadSeq: 0,
+    outputCategoryReadSeq: new Map(),
+    lastActivity: Date.now(),
+    ...overrides
+  });
+  internals.setupEventHandlers(id, client);
+
+  return { id, client };
+}
+
+/**
+ * Have the fake adapter send an 'output' event
+ */
+export function emitOutput(session: FakeSession, category: string, output: string): void {
+  session.client.emit('output', { body: { category, output } });
+}
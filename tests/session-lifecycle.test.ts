@@ -0,0 +1,111 @@
+/**
+ * Tests for how sessions report the end of the program and count against
+ * the session limit
+ */
+
+import { describe, it, expect } from 'vitest';
+import { SessionManager } from '../src/session/session-manager.js';
+import { DebugLanguage, SessionState } from '../src/session/types.js';
+import { addFakeSession, FakeSession } from './helpers/fake-session.js';
+
+function endProgram(session: FakeSession, exitCode?: number): void {
+  if (exitCode !== undefined) {
+    session.client.emit('exited', { body: { exitCode } });
+  }
+  session.client.emit('terminated', { body: {} });
+}
+
+describe('termination', () => {
+  it('reports a normal exit with its code', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager, SessionState.RUNNING);
+
+    endProgram(session, 3);
+    expect(manager.getSessionInfo(session.id).termination).toEqual({ reason: 'exited', exitCode: 3 });
+  });
+
+  it.skipIf(process.platform === 'win32')('decodes exit codes above 128 as death by signal', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager, SessionState.RUNNING);
+
+    // A segfault, as reported by Delve
+    endProgram(session, 139);
+    expect(manager.getSessionInfo(session.id).termination).toEqual({
+      reason: 'signal',
+      exitCode: 139,
+      signal: 11
+    });
+  });
+
+  it('treats exit codes outside the signal range as exits', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const low = addFakeSession(manager, SessionState.RUNNING);
+    const high = addFakeSession(manager, SessionState.RUNNING);
+
+    endProgram(low, 128);
+    endProgram(high, 255);
+    expect(manager.getSessionInfo(low.id).termination?.reason).toBe('exited');
+    expect(manager.getSessionInfo(high.id).termination?.reason).toBe('exited');
+  });
+
+  it('picks up an exit code sent after the terminated event', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager, SessionState.RUNNING);
+
+    endProgram(session);
+    expect(manager.getSessionInfo(session.id).termination).toEqual({ reason: 'exited' });
+
+    session.client.emit('exited', { body: { exitCode: 0 } });
+    expect(manager.getSessionInfo(session.id).termination).toEqual({ reason: 'exited', exitCode: 0 });
+  });
+
+  it('attributes a program stopped by terminate_session to the debugger', async () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager, SessionState.PAUSED);
+
+    const result = await manager.terminateSession(session.id);
+    expect(result.termination?.reason).toBe('debugger');
+    expect(session.client.disconnected).toBe(true);
+  });
+});
+
+describe('session limit', () => {
+  // An unknown language fails after the limit check, without starting an adapter
+  const create = (manager: SessionManager) => manager.createSession({ language: 'cobol' as DebugLanguage });
+
+  it('refuses new sessions once the limit is reached', async () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0, maxSessions: 2 });
+    addFakeSession(manager, SessionState.RUNNING);
+    addFakeSession(manager, SessionState.CREATED);
+
+    await expect(create(manager)).rejects.toThrow('Session limit reached');
+  });
+
+  it('does not count sessions whose program ended', async () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0, maxSessions: 2 });
+    addFakeSession(manager, SessionState.RUNNING);
+    endProgram(addFakeSession(manager, SessionState.RUNNING), 0);
+
+    await expect(create(manager)).rejects.toThrow("Language 'cobol' is not supported");
+  });
+
+  it('shuts down and stops counting a session whose adapter failed', async () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0, maxSessions: 1 });
+    manager.on('error', () => {});
+    const session = addFakeSession(manager, SessionState.RUNNING);
+
+    session.client.emit('error', new Error('connection reset'));
+    expect(manager.getSessionInfo(session.id).state).toBe(SessionState.ERROR);
+    expect(session.client.disconnected).toBe(true);
+    await expect(create(manager)).rejects.toThrow("Language 'cobol' is not supported");
+  });
+
+  it('is unlimited when maxSessions is 0', async () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0, maxSessions: 0 });
+    for (let i = 0; i < 20; i++) {
+      addFakeSession(manager, SessionState.RUNNING);
+    }
+
+    await expect(create(manager)).rejects.toThrow("Language 'cobol' is not supported");
+  });
+});
@@ -0,0 +1,101 @@
+/**
+ * Tests for buffering debuggee output and reading it back with get_output
+ */
+
+import { describe, it, expect } from 'vitest';
+import { SessionManager } from '../src/session/session-manager.js';
+import { addFakeSession, emitOutput } from './helpers/fake-session.js';
+
+describe('getOutput', () => {
+  it('returns only output produced since the previous read when following', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    emitOutput(session, 'stdout', 'one\n');
+    expect(manager.getOutput(session.id).text).toBe('one\n');
+
+    emitOutput(session, 'stdout', 'two\n');
+    expect(manager.getOutput(session.id).text).toBe('two\n');
+    expect(manager.getOutput(session.id).output).toEqual([]);
+  });
+
+  it('does not consume other categories when reading with a filter', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    emitOutput(session, 'stdout', 'out 1\n');
+    emitOutput(session, 'stderr', 'err 1\n');
+    emitOutput(session, 'stdout', 'out 2\n');
+    emitOutput(session, 'stderr', 'err 2\n');
+
+    expect(manager.getOutput(session.id, { category: 'stderr' }).text).toBe('err 1\nerr 2\n');
+    expect(manager.getOutput(session.id, { category: 'stdout' }).text).toBe('out 1\nout 2\n');
+    expect(manager.getOutput(session.id, { category: 'stdout' }).text).toBe('');
+  });
+
+  it('returns what a filtered read left unread on the next unfiltered read', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    emitOutput(session, 'stdout', 'out 1\n');
+    emitOutput(session, 'stderr', 'err 1\n');
+    manager.getOutput(session.id, { category: 'stderr' });
+    emitOutput(session, 'stderr', 'err 2\n');
+
+    expect(manager.getOutput(session.id).text).toBe('out 1\nerr 2\n');
+    expect(manager.getOutput(session.id, { category: 'stderr' }).text).toBe('');
+  });
+
+  it('returns the whole buffer when not following', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    emitOutput(session, 'stdout', 'one\n');
+    manager.getOutput(session.id);
+    emitOutput(session, 'stderr', 'two\n');
+
+    const result = manager.getOutput(session.id, { follow: false });
+    expect(result.text).toBe('one\ntwo\n');
+    expect(result.output.map((o) => o.seq)).toEqual([1, 2]);
+  });
+
+  it('counts unread output that fell out of the buffer as dropped', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    // The buffer holds the latest 5000 entries
+    for (let i = 1; i <= 5003; i++) {
+      emitOutput(session, 'stdout', `${i}\n`);
+    }
+
+    const first = manager.getOutput(session.id);
+    expect(first.dropped).toBe(3);
+    expect(first.output).toHaveLength(5000);
+    expect(first.output[0].output).toBe('4\n');
+
+    // Everything since the last read is still buffered
+    emitOutput(session, 'stdout', 'more\n');
+    const next = manager.getOutput(session.id);
+    expect(next.dropped).toBe(0);
+    expect(next.text).toBe('more\n');
+  });
+
+  it('does not report output already read as dropped', () => {
+    const manager = new SessionManager({ idleTimeoutMs: 0 });
+    const session = addFakeSession(manager);
+
+    for (let i = 1; i <= 10; i++) {
+      emitOutput(session, 'stdout', `${i}\n`);
+    }
+    manager.getOutput(session.id);
+    for (let i = 11; i <= 5005; i++) {
+      emitOutput(session, 'stdout', `${i}\n`);
+    }
+
+    // 5 entries fell out, and all of them had been read
+    const result = manager.getOutput(session.id);
+    expect(result.dropped).toBe(0);
+    expect(result.output).toHaveLength(4995);
+    expect(manager.getOutput(session.id, { follow: false }).dropped).toBe(5);
+  });
+});
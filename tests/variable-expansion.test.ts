@@ -0,0 +1,101 @@
+/**
+ * Tests for variable limits and recursive expansion
+ */
+
+import { describe, it, expect } from 'vitest';
+import { SessionManager } from '../src/session/session-manager.js';
+import { Variable } from '../src/session/types.js';
+import { addFakeSession } from './helpers/fake-session.js';
+
+function variable(name: string, value: string, variablesReference: number = 0): Variable {
+  return { name, value, type: '', variablesReference, hasChildren: variablesReference > 0 };
+}
+
+function session(limits?: Record<string, number>) {
+  const manager = new SessionManager({ idleTimeoutMs: 0 });
+  const fake = addFakeSession(manager, undefined, limits ? { limits } : {});
+  return { manager, ...fake };
+}
+
+describe('variable limits', () => {
+  it('cuts long values and says how much was dropped', async () => {
+    const { manager, id, client } = session({ maxStringLength: 5, maxArrayElements: 100, maxMapEntries: 100 });
+    client.tree.set(1, [variable('s', '"hello world"')]);
+
+    const [s] = await manager.expandVariable(id, 1);
+    expect(s.value).toBe('"hell...truncated, 8 more chars');
+  });
+
+  it('replaces array elements past the limit with a marker', async () => {
+    const { manager, id, client } = session({ maxStringLength: 1000, maxArrayElements: 2, maxMapEntries: 100 });
+    client.tree.set(1, [variable('[0]', '1'), variable('[1]', '2'), variable('[2]', '3')]);
+
+    const elements = await manager.expandVariable(id, 1);
+    expect(elements.map((v) => v.name)).toEqual(['[0]', '[1]', '...']);
+    expect(elements[2].value).toBe('...truncated, 1 more elements');
+  });
+
+  it('counts children the adapter did not send in the marker', async () => {
+    const { manager, id, client } = session({ maxStringLength: 1000, maxArrayElements: 2, maxMapEntries: 100 });
+    // A slice of 10 elements, of which Delve sent the first 3
+    client.tree.set(1, [{ ...variable('xs', '[]int len: 10', 2), indexedVariables: 10 }]);
+    client.tree.set(2, [variable('[0]', '0'), variable('[1]', '1'), variable('[2]', '2')]);
+
+    const [xs] = await manager.expandVariable(id, 1, 2);
+    expect(xs.children?.map((v) => v.name)).toEqual(['[0]', '[1]', '...']);
+    expect(xs.children?.[2].value).toBe('...truncated, 8 more elements');
+  });
+
+  it('applies the map limit to named children', async () => {
+    const { manager, id, client } = session({ maxStringLength: 1000, maxArrayElements: 100, maxMapEntries: 1 });
+    client.tree.set(1, [variable('"a"', '1'), variable('"b"', '2'), variable('"c"', '3')]);
+
+    const entries = await manager.expandVariable(id, 1);
+    expect(entries.map((v) => v.value)).toEqual(['1', '...truncated, 2 more entries']);
+  });
+
+  it('leaves lists within the limits unchanged', async () => {
+    const { manager, id, client } = session();
+    client.tree.set(1, [variable('a', '1'), variable('b', '"x"')]);
+
+    expect(await manager.expandVariable(id, 1)).toEqual([variable('a', '1'), variable('b', '"x"')]);
+  });
+});
+
+describe('variable expansion', () => {
+  it('expands children up to the requested depth', async () => {
+    const { manager, id, client } = session();
+    client.tree.set(1, [variable('outer', '{...}', 2)]);
+    client.tree.set(2, [variable('inner', '{...}', 3)]);
+    client.tree.set(3, [variable('leaf', '42')]);
+
+    const [outer] = await manager.expandVariable(id, 1, 2);
+    expect(outer.children?.[0].name).toBe('inner');
+    // Depth 2 stops before the inner struct's fields
+    expect(outer.children?.[0].children).toBeUndefined();
+    expect(client.requested).toEqual([1, 2]);
+  });
+
+  it('does not follow a reference back to a variable being expanded', async () => {
+    const { manager, id, client } = session();
+    // A linked list node whose next pointer leads back to itself
+    client.tree.set(1, [variable('next', '*Node', 2), variable('value', '1')]);
+    client.tree.set(2, [variable('next', '*Node', 1), variable('value', '2')]);
+
+    const [next] = await manager.expandVariable(id, 1, 5);
+    expect(next.children?.map((v) => v.name)).toEqual(['next', 'value']);
+    expect(next.children?.[0].children).toBeUndefined();
+    expect(client.requested).toEqual([1, 2]);
+  });
+
+  it('expands each shared reference once', async () => {
+    const { manager, id, client } = session();
+    client.tree.set(1, [variable('a', '*T', 2), variable('b', '*T', 2)]);
+    client.tree.set(2, [variable('x', '1')]);
+
+    const [a, b] = await manager.expandVariable(id, 1, 3);
+    expect(a.children).toEqual([variable('x', '1')]);
+    expect(b.children).toBeUndefined();
+    expect(client.requested).toEqual([1, 2]);
+  });
+});